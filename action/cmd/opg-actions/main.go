@@ -0,0 +1,34 @@
+package main
+
+import (
+	"opg-github-actions/action/internal/commands"
+	"opg-github-actions/action/internal/commands/branchname"
+	"opg-github-actions/action/internal/commands/dockertags"
+	"opg-github-actions/action/internal/commands/release"
+	"opg-github-actions/action/internal/commands/releasenotes"
+	"opg-github-actions/action/internal/commands/semvermatch"
+	"opg-github-actions/action/internal/commands/semvertag"
+	"opg-github-actions/action/internal/commands/terraforminstall"
+	"opg-github-actions/action/internal/commands/terraformversion"
+	"opg-github-actions/action/internal/commands/updateversions"
+	"os"
+)
+
+// all of the available sub commands
+var cmds = []*commands.Command{
+	branchname.Command(),
+	semvertag.Command(),
+	terraformversion.Command(),
+	terraforminstall.Command(),
+	release.Command(),
+	releasenotes.Command(),
+	updateversions.Command(),
+	dockertags.Command(),
+	semvermatch.Command(),
+}
+
+func main() {
+	// the config command checks the config file against all other commands
+	all := append(cmds, commands.ConfigCommand(cmds))
+	os.Exit(commands.Main(os.Args[1:], all))
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"opg-github-actions/action/internal/commands"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"gopkg.in/yaml.v3"
+)
+
+// inputs used only within the action.yml steps, so do not map to flags
+var actionOnlyInputs = map[string][]string{
+	"semver": {"create_release", "release_artifact", "release_notes_flag"},
+}
+
+// TestActionInputs checks every input in the actions/<command>/action.yml
+// file maps to a flag of the command, so it can be read from INPUT_<NAME>
+func TestActionInputs(t *testing.T) {
+	for _, cmd := range cmds {
+		var (
+			content []byte
+			err     error
+			action  = struct {
+				Inputs map[string]any `yaml:"inputs"`
+			}{}
+			file = filepath.Join("..", "..", "..", "actions", cmd.Name, "action.yml")
+			fs   = flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+		)
+		if content, err = os.ReadFile(file); err != nil {
+			continue
+		}
+		yaml.Unmarshal(content, &action)
+		commands.GlobalFlags(fs, commands.NewGlobals())
+		cmd.Flags(fs)
+
+		for input := range action.Inputs {
+			if slices.Contains(actionOnlyInputs[cmd.Name], input) {
+				continue
+			}
+			flagName, ok := cmd.Inputs[input]
+			if !ok {
+				t.Errorf("[%s] input [%s] is not mapped to a flag", cmd.Name, input)
+				continue
+			}
+			if fs.Lookup(flagName) == nil {
+				t.Errorf("[%s] input [%s] maps to unknown flag [%s]", cmd.Name, input, flagName)
+			}
+		}
+	}
+}
+
+// action is the part of an actions/<command>/action.yml file used by the tests
+type action struct {
+	Inputs map[string]struct {
+		Default string `yaml:"default"`
+	} `yaml:"inputs"`
+	Runs struct {
+		Steps []struct {
+			ID  string            `yaml:"id"`
+			Env map[string]string `yaml:"env"`
+			Run string            `yaml:"run"`
+		} `yaml:"steps"`
+	} `yaml:"runs"`
+}
+
+var expression = regexp.MustCompile(`\$\{\{\s*([a-z_.]+)\s*\}\}`)
+
+// expand replaces the `${{ name }}` expressions with their values, unknown
+// expressions are left as they are
+func expand(s string, values map[string]string) string {
+	return expression.ReplaceAllStringFunc(s, func(m string) string {
+		if v, ok := values[expression.FindStringSubmatch(m)[1]]; ok {
+			return v
+		}
+		return m
+	})
+}
+
+// actionRun returns the INPUT_<NAME> env values and the arguments the `cmd`
+// step of the action passes to the binary, using the action defaults for
+// any inputs not set
+func actionRun(t *testing.T, name string, inputs map[string]string, github map[string]string) (env map[string]string, args []string) {
+	var (
+		act     = &action{}
+		values  = map[string]string{}
+		content []byte
+		err     error
+	)
+	t.Helper()
+	if content, err = os.ReadFile(filepath.Join("..", "..", "..", "actions", name, "action.yml")); err != nil {
+		t.Fatalf("[%s] failed to read action: %s", name, err.Error())
+	}
+	yaml.Unmarshal(content, act)
+	for input, def := range act.Inputs {
+		values["inputs."+input] = def.Default
+	}
+	for input, v := range inputs {
+		values["inputs."+input] = v
+	}
+	for k, v := range github {
+		values["github."+k] = v
+	}
+
+	env = map[string]string{}
+	for _, step := range act.Runs.Steps {
+		if step.ID != "cmd" {
+			continue
+		}
+		for k, v := range step.Env {
+			if strings.HasPrefix(k, commands.InputPrefix) {
+				env[k] = expand(v, values)
+			}
+		}
+		// join the line calling the binary with its continuation lines
+		var line string
+		scanner := bufio.NewScanner(strings.NewReader(step.Run))
+		for scanner.Scan() {
+			var text = strings.TrimSpace(scanner.Text())
+			if line == "" && !strings.HasPrefix(text, "${{ env.binary }}") {
+				continue
+			}
+			line += " " + strings.TrimSuffix(text, "\\")
+			if !strings.HasSuffix(text, "\\") {
+				break
+			}
+		}
+		for _, arg := range strings.Fields(expand(line, values))[1:] {
+			args = append(args, strings.ReplaceAll(arg, `"`, ""))
+		}
+	}
+	return
+}
+
+type actionConfigFixture struct {
+	Action   string
+	Inputs   map[string]string
+	Config   string
+	Output   string
+	Expected string
+}
+
+// TestActionConfig runs the command as each action would, with a config
+// file present, to check values from the config file are used for inputs
+// that are not set
+func TestActionConfig(t *testing.T) {
+	var tests = []*actionConfigFixture{
+		{
+			Action:   "branch-name",
+			Inputs:   map[string]string{"name": "feature/abcdefghij"},
+			Config:   "branch-name:\n  length: 5\n",
+			Output:   "safe",
+			Expected: "featu",
+		},
+		{
+			Action:   "terraform-version",
+			Inputs:   map[string]string{"terraform_directory": "{workspace}/terraform"},
+			Config:   "terraform-version:\n  file: main.tf\n",
+			Output:   "version",
+			Expected: "1.6.5",
+		},
+		{
+			Action:   "semver",
+			Inputs:   map[string]string{"branch_name": "master", "prerelease": "false", "test": "true"},
+			Config:   "semver:\n  without-prefix: true\n",
+			Output:   "tag",
+			Expected: "1.0.1",
+		},
+	}
+
+	for i, test := range tests {
+		var (
+			dir     = t.TempDir()
+			outputs = filepath.Join(t.TempDir(), "outputs")
+			author  = &object.Signature{Name: "go test", Email: "test@example.com"}
+			github  = map[string]string{"workspace": dir, "event_path": "", "event.repository.default_branch": "master"}
+		)
+		r, _ := git.PlainInit(dir, false)
+		w, _ := r.Worktree()
+		hash, _ := w.Commit("initial commit", &git.CommitOptions{AllowEmptyCommits: true, Author: author})
+		r.CreateTag("v1.0.0", hash, nil)
+		w.Commit("second commit", &git.CommitOptions{AllowEmptyCommits: true, Author: author})
+
+		os.MkdirAll(filepath.Join(dir, ".github"), 0755)
+		os.MkdirAll(filepath.Join(dir, "terraform"), 0755)
+		os.WriteFile(filepath.Join(dir, commands.ConfigFile), []byte(test.Config), 0644)
+		os.WriteFile(filepath.Join(dir, "terraform", "main.tf"), []byte("terraform {\n  required_version = \"1.6.5\"\n}\n"), 0644)
+
+		for k, v := range test.Inputs {
+			test.Inputs[k] = strings.ReplaceAll(v, "{workspace}", dir)
+		}
+		env, args := actionRun(t, test.Action, test.Inputs, github)
+		for k, v := range env {
+			t.Setenv(k, v)
+		}
+		t.Setenv("LOG_LEVEL", "error")
+		t.Setenv("GITHUB_WORKSPACE", dir)
+		t.Setenv("GITHUB_OUTPUT", outputs)
+
+		if code := commands.Main(append([]string{test.Action}, args...), cmds); code != commands.EXIT_OK {
+			t.Errorf("[%d] [%s] unexpected exit code [%d] for %v", i, test.Action, code, args)
+			continue
+		}
+		content, _ := os.ReadFile(outputs)
+		if expected := test.Output + "=" + test.Expected + "\n"; !strings.Contains(string(content), expected) {
+			t.Errorf("[%d] [%s] expected output [%s], actual:\n%s", i, test.Action, strings.TrimSpace(expected), content)
+		}
+		for k := range env {
+			os.Unsetenv(k)
+		}
+	}
+}
+
+// actions that read their defaults from the config file, with the inputs
+// that are skipped; the token is passed as GH_TOKEN and the others always
+// have a value as later steps of the action depend on them
+var configActions = map[string][]string{
+	"branch-name":       {},
+	"terraform-version": {},
+	"semver":            {"github_token", "prerelease", "test"},
+	"docker-tags":       {},
+}
+
+// TestActionInputDefaults checks each input is passed to the command as an
+// INPUT_<NAME> env value and has an empty default, as a default would
+// always be used over the config file
+func TestActionInputDefaults(t *testing.T) {
+	for _, cmd := range cmds {
+		skip, ok := configActions[cmd.Name]
+		if !ok {
+			continue
+		}
+		env, _ := actionRun(t, cmd.Name, map[string]string{}, map[string]string{})
+		for input := range cmd.Inputs {
+			if slices.Contains(skip, input) {
+				continue
+			}
+			value, ok := env[commands.InputName(input)]
+			if !ok {
+				t.Errorf("[%s] input [%s] is not passed as [%s]", cmd.Name, input, commands.InputName(input))
+				continue
+			}
+			if value != "" {
+				t.Errorf("[%s] input [%s] has a default of [%s], so the config file is never used", cmd.Name, input, value)
+			}
+		}
+	}
+}
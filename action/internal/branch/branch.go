@@ -0,0 +1,93 @@
+package branch
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Environment variables github actions sets that contain branch details
+// see: https://docs.github.com/en/actions/reference/variables-reference#default-environment-variables
+const (
+	EnvHeadRef string = "GITHUB_HEAD_REF" // source branch of a pull request, only set for pull request events
+	EnvRef     string = "GITHUB_REF"      // fully formed ref that triggered the workflow
+)
+
+const ErrNotFound string = "error: branch name not passed and not found in environment values (%s, %s)."
+
+type Source string
+
+const (
+	SOURCE_INPUT    Source = "input"
+	SOURCE_HEAD_REF Source = Source(EnvHeadRef)
+	SOURCE_REF      Source = Source(EnvRef)
+)
+
+// patterns for refs that need more than a prefix removed
+var (
+	pullRequestRef = regexp.MustCompile(`^refs/pull/(?P<number>[0-9]+)/(merge|head)$`)
+	mergeQueueRef  = regexp.MustCompile(`^refs/heads/gh-readonly-queue/.+/pr-(?P<number>[0-9]+)-[0-9a-f]+$`)
+)
+
+// Branch contains the details of the resolved branch
+type Branch struct {
+	Name      string // name of the branch to use for naming things
+	Reference string // short form of the git reference that can be found in the repository
+	Source    Source // where the value was found
+}
+
+// FromRef converts a git reference into a branch name and short form reference
+//
+//	`refs/heads/my-branch` => `my-branch`, `my-branch`
+//	`refs/tags/v1.0.0` => `v1.0.0`, `v1.0.0`
+//	`refs/pull/12/merge` => `pr-12`, `pull/12/merge`
+//	`refs/heads/gh-readonly-queue/main/pr-12-f2bd016` => `pr-12`, `gh-readonly-queue/main/pr-12-f2bd016`
+//
+// Values that are not fully formed references are returned as they are
+func FromRef(ref string) (name string, reference string) {
+	if match := mergeQueueRef.FindStringSubmatch(ref); len(match) > 0 {
+		name = fmt.Sprintf("pr-%s", match[mergeQueueRef.SubexpIndex("number")])
+		reference = strings.TrimPrefix(ref, "refs/heads/")
+		return
+	}
+	if match := pullRequestRef.FindStringSubmatch(ref); len(match) > 0 {
+		name = fmt.Sprintf("pr-%s", match[pullRequestRef.SubexpIndex("number")])
+		reference = strings.TrimPrefix(ref, "refs/")
+		return
+	}
+
+	reference = strings.TrimPrefix(ref, "refs/heads/")
+	reference = strings.TrimPrefix(reference, "refs/tags/")
+	name = reference
+	return
+}
+
+// Resolve works out the branch to use, in order of:
+//
+//   - `value` (such as a command argument)
+//   - GITHUB_HEAD_REF environment value (pull requests)
+//   - GITHUB_REF environment value (push, merge queue and others)
+//
+// An error is returned when none are set
+func Resolve(value string) (branch *Branch, err error) {
+	var sources = []struct {
+		value  string
+		source Source
+	}{
+		{value: value, source: SOURCE_INPUT},
+		{value: os.Getenv(EnvHeadRef), source: SOURCE_HEAD_REF},
+		{value: os.Getenv(EnvRef), source: SOURCE_REF},
+	}
+
+	for _, src := range sources {
+		if src.value != "" {
+			branch = &Branch{Source: src.source}
+			branch.Name, branch.Reference = FromRef(src.value)
+			return
+		}
+	}
+
+	err = fmt.Errorf(ErrNotFound, EnvHeadRef, EnvRef)
+	return
+}
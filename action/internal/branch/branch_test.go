@@ -0,0 +1,80 @@
+package branch
+
+import "testing"
+
+type refFixture struct {
+	Ref               string
+	ExpectedName      string
+	ExpectedReference string
+}
+
+func TestBranchFromRef(t *testing.T) {
+
+	var tests = []*refFixture{
+		{Ref: "my-branch", ExpectedName: "my-branch", ExpectedReference: "my-branch"},
+		{Ref: "dependabot/go/thing", ExpectedName: "dependabot/go/thing", ExpectedReference: "dependabot/go/thing"},
+		{Ref: "refs/heads/my-branch", ExpectedName: "my-branch", ExpectedReference: "my-branch"},
+		{Ref: "refs/heads/feature/my-branch", ExpectedName: "feature/my-branch", ExpectedReference: "feature/my-branch"},
+		{Ref: "refs/tags/v1.0.0", ExpectedName: "v1.0.0", ExpectedReference: "v1.0.0"},
+		{Ref: "refs/pull/12/merge", ExpectedName: "pr-12", ExpectedReference: "pull/12/merge"},
+		{Ref: "refs/pull/12/head", ExpectedName: "pr-12", ExpectedReference: "pull/12/head"},
+		{
+			Ref:               "refs/heads/gh-readonly-queue/main/pr-123-f2bd01600ded17a4f3f2c8348443b33cd48c8902",
+			ExpectedName:      "pr-123",
+			ExpectedReference: "gh-readonly-queue/main/pr-123-f2bd01600ded17a4f3f2c8348443b33cd48c8902",
+		},
+	}
+
+	for _, test := range tests {
+		name, reference := FromRef(test.Ref)
+		if name != test.ExpectedName {
+			t.Errorf("error with ref [%s] name, expected [%s] actual [%s]", test.Ref, test.ExpectedName, name)
+		}
+		if reference != test.ExpectedReference {
+			t.Errorf("error with ref [%s] reference, expected [%s] actual [%s]", test.Ref, test.ExpectedReference, reference)
+		}
+	}
+}
+
+type resolveFixture struct {
+	Value          string
+	HeadRef        string
+	Ref            string
+	ShouldError    bool
+	ExpectedName   string
+	ExpectedSource Source
+}
+
+func TestBranchResolve(t *testing.T) {
+
+	var tests = []*resolveFixture{
+		{Value: "my-branch", HeadRef: "other", Ref: "refs/heads/main", ExpectedName: "my-branch", ExpectedSource: SOURCE_INPUT},
+		{Value: "", HeadRef: "feature/thing", Ref: "refs/pull/4/merge", ExpectedName: "feature/thing", ExpectedSource: SOURCE_HEAD_REF},
+		{Value: "", HeadRef: "", Ref: "refs/heads/main", ExpectedName: "main", ExpectedSource: SOURCE_REF},
+		{Value: "", HeadRef: "", Ref: "refs/pull/4/merge", ExpectedName: "pr-4", ExpectedSource: SOURCE_REF},
+		{Value: "", HeadRef: "", Ref: "", ShouldError: true},
+	}
+
+	for i, test := range tests {
+		t.Setenv(EnvHeadRef, test.HeadRef)
+		t.Setenv(EnvRef, test.Ref)
+
+		actual, err := Resolve(test.Value)
+		if test.ShouldError {
+			if err == nil {
+				t.Errorf("[%d] expected an error, but did not get one", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+			continue
+		}
+		if actual.Name != test.ExpectedName {
+			t.Errorf("[%d] expected name [%s] actual [%s]", i, test.ExpectedName, actual.Name)
+		}
+		if actual.Source != test.ExpectedSource {
+			t.Errorf("[%d] expected source [%s] actual [%s]", i, test.ExpectedSource, actual.Source)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package branchname
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"opg-github-actions/action/internal/branch"
+	"opg-github-actions/action/internal/commands"
+	"opg-github-actions/action/internal/strs"
+	"opg-github-actions/action/internal/tickets"
+	"regexp"
+	"strings"
+)
+
+// Errors
+const (
+	ErrEmptySafeName   string = "error: branch name [%s] contains no alphanumeric characters, so would generate an empty value."
+	ErrReservedPattern string = "error: invalid reserved name pattern [%s]"
+	ErrReservedRewrite string = "error: branch name [%s] is reserved and could not be rewritten to a value that is not reserved."
+)
+
+type Options struct {
+	Source         string   // the value to convert into a safe branch name, when empty its found from the environment
+	Length         int      // max length of the safe string
+	HashSuffix     bool     // when truncating, replace the end with a hash of the full value
+	Transliterate  bool     // convert unicode characters to ascii before cleaning (é => e)
+	TicketPattern  string   // regex used to find ticket ids within the source
+	TicketName     bool     // when a ticket is found, build the safe name as <ticket><short-desc>
+	Reserved       []string // names (or regex patterns) the safe values must not match
+	ReservedPrefix string   // prefix added to safe values that match a reserved name
+}
+
+// Result contains the values generated for the branch
+type Result struct {
+	BranchName string   `json:"branch_name"` // the branch name as found
+	Safe       string   `json:"safe"`        // cleaned and shortened version of the branch name
+	FullLength string   `json:"full_length"` // cleaned version of the branch name
+	Ticket     string   `json:"ticket"`      // first ticket id found
+	Tickets    []string `json:"tickets"`     // all ticket ids found
+	Source     string   `json:"source"`      // where the branch name came from
+	Reserved   bool     `json:"reserved"`    // if the safe value was rewritten as it matched a reserved name
+}
+
+// Outputs returns the string values used for github outputs
+func (self *Result) Outputs() (outputs map[string]string) {
+	outputs = map[string]string{}
+	if self == nil {
+		return
+	}
+	outputs = map[string]string{
+		"branch_name": self.BranchName,
+		"safe":        self.Safe,
+		"full_length": self.FullLength,
+		"ticket":      self.Ticket,
+		"tickets":     strings.Join(self.Tickets, ","),
+		"source":      self.Source,
+		"reserved":    fmt.Sprintf("%t", self.Reserved),
+	}
+	return
+}
+
+// newRunOptions returns the default options
+func newRunOptions() *Options {
+	return &Options{
+		Source:         "",
+		Length:         14,
+		HashSuffix:     false,
+		Transliterate:  false,
+		TicketPattern:  tickets.DefaultPattern,
+		TicketName:     false,
+		Reserved:       []string{},
+		ReservedPrefix: "br",
+	}
+}
+
+// shorten truncates the value to the max length, using a hash suffix
+// if enabled
+func shorten(s string, options *Options) string {
+	if options.HashSuffix {
+		return strs.TruncateWithHash(s, options.Length)
+	}
+	return strs.Truncate(s, options.Length)
+}
+
+// isReserved checks each of the reserved patterns to see if they match the
+// whole of any of the values passed. Plain names will only match exactly.
+func isReserved(reserved []string, values ...string) (is bool, err error) {
+	for _, pattern := range reserved {
+		var exp *regexp.Regexp
+
+		if pattern = strings.TrimSpace(pattern); pattern == "" {
+			continue
+		}
+		if exp, err = regexp.Compile(fmt.Sprintf("^(?:%s)$", pattern)); err != nil {
+			err = fmt.Errorf(ErrReservedPattern, pattern)
+			return
+		}
+		for _, v := range values {
+			if exp.MatchString(v) {
+				is = true
+				return
+			}
+		}
+	}
+	return
+}
+
+// rewriteReserved deterministically changes the safe values so they no longer
+// match a reserved name, first by adding the reserved prefix and if that is
+// still reserved, by using a hash of the full length value
+func rewriteReserved(full string, options *Options) (safeAndShort string, safe string, err error) {
+	var reserved bool
+
+	safe = options.ReservedPrefix + full
+	safeAndShort = shorten(safe, options)
+	if reserved, err = isReserved(options.Reserved, safeAndShort, safe); err != nil || !reserved {
+		return
+	}
+
+	safe = strs.Hash(full, options.Length)
+	safeAndShort = safe
+	if reserved, err = isReserved(options.Reserved, safe); err == nil && reserved {
+		err = fmt.Errorf(ErrReservedRewrite, full)
+	}
+	return
+}
+
+// Run processes the input and returns the values
+func Run(lg *slog.Logger, options *Options) (result *Result, err error) {
+	var (
+		safe         string
+		safeAndShort string
+		reserved     bool
+		found        []string
+		resolved     *branch.Branch
+		ticket       string = ""
+		source       string = ""
+		name         string = ""
+	)
+	// use the source, or fall back to the github environment values and remove
+	// any head references for fully formed branch values
+	if resolved, err = branch.Resolve(options.Source); err != nil {
+		return
+	}
+	lg.Info("branch name found ... ", "branch", resolved.Name, "source", string(resolved.Source))
+	source = resolved.Name
+	name = source
+
+	// look for ticket ids within the branch name
+	if found, err = tickets.Find(source, options.TicketPattern); err != nil {
+		lg.Error("error finding tickets with pattern", "err", err.Error(), "pattern", options.TicketPattern)
+		return
+	}
+	if len(found) > 0 {
+		ticket = found[0]
+	}
+	// when enabled, lead the name with the ticket so it survives truncation
+	if options.TicketName && ticket != "" {
+		name = ticket + tickets.Description(source, ticket)
+	}
+
+	if options.Transliterate {
+		name = strs.Transliterate(name)
+	}
+	safe = strs.Clean(name)
+	if safe == "" {
+		err = fmt.Errorf(ErrEmptySafeName, source)
+		return
+	}
+	safeAndShort = shorten(safe, options)
+
+	// make sure the values dont clash with reserved names
+	if reserved, err = isReserved(options.Reserved, safeAndShort, safe); err != nil {
+		return
+	}
+	if reserved {
+		lg.Warn("branch name matches a reserved name, rewriting ... ", "safe", safeAndShort)
+		if safeAndShort, safe, err = rewriteReserved(safe, options); err != nil {
+			return
+		}
+	}
+
+	result = &Result{
+		BranchName: source,
+		Safe:       safeAndShort,
+		FullLength: safe,
+		Ticket:     ticket,
+		Tickets:    found,
+		Source:     string(resolved.Source),
+		Reserved:   reserved,
+	}
+
+	return
+}
+
+// Command returns the branch-name command, with the flags registered
+// against its own options
+func Command() *commands.Command {
+	return &commands.Command{
+		Name:        "branch-name",
+		Description: "Generate a safe, shortened version of the branch name.",
+		Inputs: map[string]string{
+			"name":            "source",
+			"length":          "length",
+			"hash_suffix":     "hash-suffix",
+			"transliterate":   "transliterate",
+			"ticket_pattern":  "ticket-pattern",
+			"ticket_name":     "ticket-name",
+			"reserved":        "reserved",
+			"reserved_prefix": "reserved-prefix",
+		},
+		Flags: func(fs *flag.FlagSet) func(lg *slog.Logger, globals *commands.Globals) (commands.Result, error) {
+			var options = newRunOptions()
+
+			fs.IntVar(&options.Length, "length", options.Length, "Set the max length of the safe string to return")
+			fs.StringVar(&options.Source, "source", options.Source, "The value to convert into a safe branch name. When empty, GITHUB_HEAD_REF and then GITHUB_REF are used.")
+			fs.BoolVar(&options.HashSuffix, "hash-suffix", options.HashSuffix, "When truncating, replace the end of the safe string with a short hash of the full value.")
+			fs.BoolVar(&options.Transliterate, "transliterate", options.Transliterate, "Convert unicode characters to their closest ascii version before cleaning.")
+			fs.StringVar(&options.TicketPattern, "ticket-pattern", options.TicketPattern, "Regex pattern used to find ticket ids within the source.")
+			fs.Func("reserved", "Comma separated list of reserved names (or regex patterns) the safe values must not match.", func(v string) error {
+				options.Reserved = strings.Split(v, ",")
+				return nil
+			})
+			fs.StringVar(&options.ReservedPrefix, "reserved-prefix", options.ReservedPrefix, "Prefix added to safe values that match a reserved name.")
+			fs.BoolVar(&options.TicketName, "ticket-name", options.TicketName, "When a ticket id is found, build the safe string as the ticket followed by the description.")
+
+			return func(lg *slog.Logger, globals *commands.Globals) (commands.Result, error) {
+				return Run(lg, options)
+			}
+		},
+	}
+}
@@ -0,0 +1,289 @@
+package branchname
+
+import (
+	"opg-github-actions/action/internal/branch"
+	"opg-github-actions/action/internal/logger"
+	"testing"
+)
+
+type cmdFixture struct {
+	Length        int
+	Source        string
+	HashSuffix    bool
+	Transliterate bool
+	TicketPattern string
+	TicketName    bool
+	Error         bool
+	Expected      map[string]string
+}
+
+func TestBranchNameCommandWorking(t *testing.T) {
+	lg := logger.New("error", "text")
+	tests := []cmdFixture{
+		{
+
+			Source:   "my-feature-branch",
+			Length:   20,
+			Expected: map[string]string{"full_length": "myfeaturebranch", "safe": "myfeaturebranch", "branch_name": "my-feature-branch"},
+		},
+		{
+			Length:   12,
+			Source:   "my-feature??",
+			Expected: map[string]string{"full_length": "myfeature", "safe": "myfeature", "branch_name": "my-feature??"},
+		},
+		{
+			Length:   14,
+			Source:   "long/string/with/slashes",
+			Expected: map[string]string{"full_length": "longstringwithslashes", "safe": "longstringwith"},
+		},
+		{
+			Length:   14,
+			Source:   "long/string-with-others?!.><~@#",
+			Expected: map[string]string{"full_length": "longstringwithothers", "safe": "longstringwith"},
+		},
+		{
+			Length:   14,
+			Source:   "long/string-with-others?!.><~@#♥",
+			Expected: map[string]string{"full_length": "longstringwithothers", "safe": "longstringwith", "branch_name": "long/string-with-others?!.><~@#♥"},
+		},
+		{
+			Length:   6,
+			Source:   "long/string",
+			Expected: map[string]string{"full_length": "longstring", "safe": "longst", "branch_name": "long/string"},
+		},
+		{
+			Length:   14,
+			Source:   "renovate/my-feature-thingy-update",
+			Expected: map[string]string{"full_length": "renovatemyfeaturethingyupdate", "safe": "renovatemyfeat", "branch_name": "renovate/my-feature-thingy-update"},
+		},
+		{
+			Length:     14,
+			Source:     "feature/payments-refactor-a",
+			HashSuffix: true,
+			Expected:   map[string]string{"full_length": "featurepaymentsrefactora", "safe": "featurepae20ff", "branch_name": "feature/payments-refactor-a"},
+		},
+		{
+			Length:     14,
+			Source:     "feature/payments-refactor-b",
+			HashSuffix: true,
+			Expected:   map[string]string{"full_length": "featurepaymentsrefactorb", "safe": "featurepb9652f", "branch_name": "feature/payments-refactor-b"},
+		},
+		{
+			Length:   14,
+			Source:   "fix/café-menu",
+			Expected: map[string]string{"full_length": "fixcafmenu", "safe": "fixcafmenu", "branch_name": "fix/café-menu"},
+		},
+		{
+			Length:        14,
+			Source:        "fix/café-menu",
+			Transliterate: true,
+			Expected:      map[string]string{"full_length": "fixcafemenu", "safe": "fixcafemenu", "branch_name": "fix/café-menu"},
+		},
+		{
+			Length:        14,
+			Source:        "feature/борщ",
+			Transliterate: true,
+			Expected:      map[string]string{"full_length": "featureborshch", "safe": "featureborshch"},
+		},
+		{
+			Length:   14,
+			Source:   "ABC-1234-some-description",
+			Expected: map[string]string{"full_length": "abc1234somedescription", "safe": "abc1234somedes", "ticket": "ABC-1234", "tickets": "ABC-1234"},
+		},
+		{
+			Length:     14,
+			Source:     "feature/ABC-1234/add-a-new-thing",
+			TicketName: true,
+			Expected:   map[string]string{"full_length": "abc1234addanewthing", "safe": "abc1234addanew", "ticket": "ABC-1234", "tickets": "ABC-1234", "branch_name": "feature/ABC-1234/add-a-new-thing"},
+		},
+		{
+			Length:     14,
+			Source:     "feature/abc-12-fix-def-34",
+			TicketName: true,
+			// lower case ids only match with a custom pattern
+			TicketPattern: `(?i)\b[a-z]+-[0-9]+\b`,
+			Expected:      map[string]string{"full_length": "abc12fixdef34", "safe": "abc12fixdef34", "ticket": "abc-12", "tickets": "abc-12,def-34"},
+		},
+		{
+			Length:     14,
+			Source:     "feature/no-ticket-here",
+			TicketName: true,
+			Expected:   map[string]string{"full_length": "featurenotickethere", "safe": "featurenoticke", "ticket": "", "tickets": ""},
+		},
+		{
+			Length:     20,
+			Source:     "my-feature-branch",
+			HashSuffix: true,
+			Expected:   map[string]string{"full_length": "myfeaturebranch", "safe": "myfeaturebranch", "branch_name": "my-feature-branch"},
+		},
+	}
+
+	for _, test := range tests {
+		res, err := Run(lg, &Options{
+			Source:        test.Source,
+			Length:        test.Length,
+			HashSuffix:    test.HashSuffix,
+			Transliterate: test.Transliterate,
+			TicketPattern: test.TicketPattern,
+			TicketName:    test.TicketName,
+		})
+		actual := res.Outputs()
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+		}
+		for k, v := range test.Expected {
+			if actual[k] != v {
+				t.Errorf("error: [%s] expected [%s] actual [%s]", k, v, actual[k])
+			}
+		}
+	}
+}
+
+type envFixture struct {
+	Source   string
+	HeadRef  string
+	Ref      string
+	Error    bool
+	Expected map[string]string
+}
+
+func TestBranchNameCommandFromEnvironment(t *testing.T) {
+	lg := logger.New("error", "text")
+	tests := []envFixture{
+		{
+			Source:   "my-feature-branch",
+			HeadRef:  "other-branch",
+			Ref:      "refs/pull/1/merge",
+			Expected: map[string]string{"branch_name": "my-feature-branch", "safe": "myfeaturebranc", "source": "input"},
+		},
+		{
+			HeadRef:  "feature/from-head",
+			Ref:      "refs/pull/1/merge",
+			Expected: map[string]string{"branch_name": "feature/from-head", "safe": "featurefromhea", "source": "GITHUB_HEAD_REF"},
+		},
+		{
+			Ref:      "refs/heads/main",
+			Expected: map[string]string{"branch_name": "main", "safe": "main", "source": "GITHUB_REF"},
+		},
+		{
+			Ref:      "refs/heads/gh-readonly-queue/main/pr-42-f2bd01600ded17a4f3f2c8348443b33cd48c8902",
+			Expected: map[string]string{"branch_name": "pr-42", "safe": "pr42", "source": "GITHUB_REF"},
+		},
+		{
+			Error: true,
+		},
+	}
+
+	for i, test := range tests {
+		t.Setenv(branch.EnvHeadRef, test.HeadRef)
+		t.Setenv(branch.EnvRef, test.Ref)
+
+		res, err := Run(lg, &Options{Source: test.Source, Length: 14})
+		actual := res.Outputs()
+		if test.Error && err == nil {
+			t.Errorf("[%d] expected an error, but did not get one", i)
+		} else if !test.Error && err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+		}
+		for k, v := range test.Expected {
+			if actual[k] != v {
+				t.Errorf("[%d] error: [%s] expected [%s] actual [%s]", i, k, v, actual[k])
+			}
+		}
+	}
+}
+
+type reservedFixture struct {
+	Source         string
+	Length         int
+	Reserved       []string
+	ReservedPrefix string
+	Error          bool
+	Expected       map[string]string
+}
+
+func TestBranchNameCommandReserved(t *testing.T) {
+	lg := logger.New("error", "text")
+	tests := []reservedFixture{
+		{
+			Source:         "production",
+			Length:         14,
+			Reserved:       []string{"main", "production"},
+			ReservedPrefix: "br",
+			Expected:       map[string]string{"safe": "brproduction", "full_length": "brproduction", "reserved": "true"},
+		},
+		{
+			Source:         "production-fix-for-thing",
+			Length:         10,
+			Reserved:       []string{"main", "production"},
+			ReservedPrefix: "br",
+			Expected:       map[string]string{"safe": "brproducti", "full_length": "brproductionfixforthing", "reserved": "true"},
+		},
+		{
+			Source:         "feature/not-reserved",
+			Length:         14,
+			Reserved:       []string{"main", "production"},
+			ReservedPrefix: "br",
+			Expected:       map[string]string{"safe": "featurenotrese", "reserved": "false"},
+		},
+		{
+			// patterns need to match the whole value
+			Source:         "preproduction",
+			Length:         14,
+			Reserved:       []string{"pre.*", "main"},
+			ReservedPrefix: "br",
+			Expected:       map[string]string{"safe": "brpreproductio", "reserved": "true"},
+		},
+		{
+			Source:         "maintenance",
+			Length:         14,
+			Reserved:       []string{"main"},
+			ReservedPrefix: "br",
+			Expected:       map[string]string{"safe": "maintenance", "reserved": "false"},
+		},
+		{
+			// prefixed value is also reserved, so fall back to the hash
+			Source:         "main",
+			Length:         14,
+			Reserved:       []string{"main", "br.*"},
+			ReservedPrefix: "br",
+			Expected:       map[string]string{"safe": "0d6e4079e36703", "full_length": "0d6e4079e36703", "reserved": "true"},
+		},
+		{
+			Source:   "main",
+			Length:   14,
+			Reserved: []string{"[a-z"},
+			Error:    true,
+		},
+		{
+			Source: "!!/--/??",
+			Length: 14,
+			Error:  true,
+		},
+		{
+			Source: "♥♥♥",
+			Length: 14,
+			Error:  true,
+		},
+	}
+
+	for i, test := range tests {
+		res, err := Run(lg, &Options{
+			Source:         test.Source,
+			Length:         test.Length,
+			Reserved:       test.Reserved,
+			ReservedPrefix: test.ReservedPrefix,
+		})
+		actual := res.Outputs()
+		if test.Error && err == nil {
+			t.Errorf("[%d] expected an error, but did not get one", i)
+		} else if !test.Error && err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+		}
+		for k, v := range test.Expected {
+			if actual[k] != v {
+				t.Errorf("[%d] error: [%s] expected [%s] actual [%s]", i, k, v, actual[k])
+			}
+		}
+	}
+}
@@ -0,0 +1,194 @@
+// Package commands provides the shared setup for all sub commands of the
+// opg-actions binary - global flags, logging, output and exit codes.
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"opg-github-actions/action/internal/logger"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Exit codes
+const (
+	EXIT_OK    int = 0 // command ran without error
+	EXIT_ERROR int = 1 // command failed
+	EXIT_USAGE int = 2 // unknown command or invalid flags
+)
+
+// Errors
+const (
+	ErrNoCommand      string = "error: no command given"
+	ErrUnknownCommand string = "error: unknown command [%s]"
+)
+
+const Name string = "opg-actions"
+
+// Globals are the flags shared by every command
+type Globals struct {
+	LogLevel  string   // log level (debug, info, warn, error)
+	LogFormat string   // log handler format (text, json)
+	Directory string   // directory the command operates from
+	Token     string   // github token used for authenticated calls
+	Config    string   // path to the config file, defaults to ConfigFile within the directory
+	Output    string   // format of the result (text, json)
+	Args      []string // positional arguments after the command name
+}
+
+// Command is a sub command of the binary.
+//
+// Flags registers the command flags against the flag set and returns a
+// function that runs the command with the parsed values, which allows each
+// command to keep its options out of package level state.
+//
+// Inputs maps the input names used in the actions/*/action.yml file to the
+// flag they set, so the values can be read from the INPUT_<NAME> env values
+// github sets for actions.
+type Command struct {
+	Name        string
+	Description string
+	Inputs      map[string]string
+	Flags       func(fs *flag.FlagSet) (run func(lg *slog.Logger, globals *Globals) (Result, error))
+}
+
+// NewGlobals returns the default global values; env values and the config
+// file are applied over these when the command runs
+func NewGlobals() *Globals {
+	return &Globals{
+		LogLevel:  "info",
+		LogFormat: "text",
+		Directory: "",
+		Token:     "",
+		Config:    "",
+		Output:    OUTPUT_TEXT,
+	}
+}
+
+// GlobalFlags registers the global flags against the flag set
+func GlobalFlags(fs *flag.FlagSet, globals *Globals) {
+	fs.StringVar(&globals.LogLevel, "log-level", globals.LogLevel, "Log level to use (debug, info, warn, error).")
+	fs.StringVar(&globals.LogFormat, "log-format", globals.LogFormat, "Log format to use (text, json).")
+	fs.StringVar(&globals.Directory, "directory", globals.Directory, "Directory path to operate from.")
+	fs.StringVar(&globals.Token, "token", globals.Token, "GitHub token used for authenticated calls. Defaults to GH_TOKEN or GITHUB_TOKEN.")
+	fs.StringVar(&globals.Output, "output", globals.Output, "Format of the result (text, json). Logs are written to stderr for json.")
+	fs.StringVar(&globals.Config, "config", globals.Config, fmt.Sprintf("Path to the config file. (default: <directory>/%s)", ConfigFile))
+}
+
+// usage writes the list of commands
+func usage(w io.Writer, cmds []*Command) {
+	fmt.Fprintf(w, "Usage: %s [global flags] <command> [flags]\n\nCommands:\n", Name)
+	for _, cmd := range cmds {
+		fmt.Fprintf(w, "  %-20s %s\n", cmd.Name, cmd.Description)
+	}
+	fmt.Fprintf(w, "\nRun '%s <command> -h' for command flags.\n", Name)
+}
+
+// find returns the command matching the name
+func find(cmds []*Command, name string) (cmd *Command) {
+	if i := slices.IndexFunc(cmds, func(c *Command) bool { return c.Name == name }); i >= 0 {
+		cmd = cmds[i]
+	}
+	return
+}
+
+// Main parses the arguments, runs the selected command and outputs its
+// result, returning the exit code to use.
+//
+// Flags not set on the command line are filled in from action inputs, env
+// values and then the config file (see applyDefaults).
+//
+// Global flags can be set either before or after the command name:
+//
+//	opg-actions --log-level debug semver --prerelease
+//	opg-actions semver --prerelease --log-level debug
+func Main(args []string, cmds []*Command) (code int) {
+	var (
+		err     error
+		res     Result
+		run     func(lg *slog.Logger, globals *Globals) (Result, error)
+		lg      *slog.Logger
+		logs    io.Writer = os.Stdout
+		cmd     *Command
+		cfg     *Config
+		globals = NewGlobals()
+		root    = flag.NewFlagSet(Name, flag.ContinueOnError)
+	)
+	GlobalFlags(root, globals)
+	root.Usage = func() { usage(root.Output(), cmds) }
+
+	if err = root.Parse(args); err != nil {
+		return exitCode(err)
+	}
+	if root.NArg() == 0 {
+		fmt.Fprintln(root.Output(), ErrNoCommand)
+		root.Usage()
+		return EXIT_USAGE
+	}
+	if cmd = find(cmds, root.Arg(0)); cmd == nil {
+		fmt.Fprintf(root.Output(), ErrUnknownCommand+"\n", root.Arg(0))
+		root.Usage()
+		return EXIT_USAGE
+	}
+
+	fs := flag.NewFlagSet(Name+" "+cmd.Name, flag.ContinueOnError)
+	GlobalFlags(fs, globals)
+	run = cmd.Flags(fs)
+	if globals.Args, err = parse(fs, root.Args()[1:]); err != nil {
+		return exitCode(err)
+	}
+	if cfg, err = applyDefaults(cmd, fs, root, globals); err != nil {
+		fmt.Fprintln(fs.Output(), err.Error())
+		return EXIT_USAGE
+	}
+	if err = validOutput(globals.Output); err != nil {
+		fmt.Fprintln(fs.Output(), err.Error())
+		return EXIT_USAGE
+	}
+	// keep stdout for only the json document
+	if globals.Output == OUTPUT_JSON {
+		logs = os.Stderr
+	}
+
+	lg = logger.Create(globals.LogLevel, globals.LogFormat, logs)
+	if cfg.Path != "" {
+		lg.Debug("using config file ... ", "path", cfg.Path)
+		if unknown := cfg.Unknown(cmds); len(unknown) > 0 {
+			lg.Warn("config file has unknown keys, see `config validate`.", "keys", strings.Join(unknown, ","))
+		}
+	}
+	lg.Debug("running command ... ", "command", cmd.Name)
+	res, err = run(lg, globals)
+	if err != nil {
+		lg.Error(err.Error())
+		code = EXIT_ERROR
+	}
+	output(lg, os.Stdout, globals.Output, cmd.Name, res, err)
+	return
+}
+
+// parse handles the flags, allowing them to be mixed with positional
+// arguments, which are returned
+func parse(fs *flag.FlagSet, args []string) (positional []string, err error) {
+	positional = []string{}
+	for {
+		if err = fs.Parse(args); err != nil || fs.NArg() == 0 {
+			return
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// exitCode converts a flag parsing error into an exit code, asking for help
+// is not treated as an error
+func exitCode(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return EXIT_OK
+	}
+	return EXIT_USAGE
+}
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"log/slog"
+	"testing"
+)
+
+type mainFixture struct {
+	Args              []string
+	ExpectedCode      int
+	ExpectedValue     string
+	ExpectedDirectory string
+	ExpectedLevel     string
+}
+
+// testCommand returns a command that records the values it was run with
+func testCommand(value *string, globals **Globals) *Command {
+	return &Command{
+		Name:        "test",
+		Description: "test command",
+		Inputs:      map[string]string{"test_value": "value", "dir": "directory"},
+		Flags: func(fs *flag.FlagSet) func(lg *slog.Logger, g *Globals) (Result, error) {
+			var v = fs.String("value", "default", "test value")
+			return func(lg *slog.Logger, g *Globals) (Result, error) {
+				*value = *v
+				*globals = g
+				if *v == "fail" {
+					return nil, errors.New("failed")
+				}
+				return Outputs{"value": *v}, nil
+			}
+		},
+	}
+}
+
+func TestCommandsMain(t *testing.T) {
+	var fixtures = []mainFixture{
+		{Args: []string{"test"}, ExpectedCode: EXIT_OK, ExpectedValue: "default", ExpectedLevel: "error"},
+		{Args: []string{"--directory", "./a", "test", "--value", "x"}, ExpectedCode: EXIT_OK, ExpectedValue: "x", ExpectedDirectory: "./a", ExpectedLevel: "error"},
+		{Args: []string{"test", "--value", "x", "--log-level", "warn", "--directory", "./b"}, ExpectedCode: EXIT_OK, ExpectedValue: "x", ExpectedDirectory: "./b", ExpectedLevel: "warn"},
+		{Args: []string{"test", "--value", "fail"}, ExpectedCode: EXIT_ERROR, ExpectedValue: "fail", ExpectedLevel: "error"},
+		{Args: []string{}, ExpectedCode: EXIT_USAGE},
+		{Args: []string{"unknown"}, ExpectedCode: EXIT_USAGE},
+		{Args: []string{"test", "--not-a-flag"}, ExpectedCode: EXIT_USAGE},
+		{Args: []string{"test", "-h"}, ExpectedCode: EXIT_OK},
+	}
+	t.Setenv("LOG_LEVEL", "error")
+
+	for i, f := range fixtures {
+		var (
+			value   string
+			globals *Globals
+			cmds    = []*Command{testCommand(&value, &globals)}
+		)
+		code := Main(f.Args, cmds)
+		if code != f.ExpectedCode {
+			t.Errorf("[%d] exit code expected [%d] actual [%d]", i, f.ExpectedCode, code)
+		}
+		if value != f.ExpectedValue {
+			t.Errorf("[%d] value expected [%s] actual [%s]", i, f.ExpectedValue, value)
+		}
+		if globals != nil && (globals.Directory != f.ExpectedDirectory || globals.LogLevel != f.ExpectedLevel) {
+			t.Errorf("[%d] globals expected [%s, %s] actual [%s, %s]", i, f.ExpectedDirectory, f.ExpectedLevel, globals.Directory, globals.LogLevel)
+		}
+	}
+}
@@ -0,0 +1,358 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Errors
+const (
+	ErrConfigParse   string = "error: failed to parse config file [%s]: %s"
+	ErrConfigSection string = "error: config file [%s]: [%s] must be a map of flag names to values"
+	ErrConfigValue   string = "error: invalid value [%s] for [%s] from %s: %s"
+	ErrConfigUnknown string = "error: config file [%s] has unknown keys: %s"
+	ErrConfigAction  string = "error: unknown config action [%s], expected [validate]"
+)
+
+const configValidate string = "validate"
+
+// ConfigFile is the default location of the config file, relative to the
+// directory
+const ConfigFile string = ".github/opg-actions.yml"
+
+// EnvPrefix is added to the upper case flag name (with `-` replaced by `_`)
+// to find env values for flags, so `--default-bump` reads OPG_ACTIONS_DEFAULT_BUMP
+const EnvPrefix string = "OPG_ACTIONS_"
+
+// InputPrefix is used by github for action inputs, the input name is upper
+// cased and has spaces replaced with `_`
+const InputPrefix string = "INPUT_"
+
+// envAliases are older env values that are still used for some flags, they
+// are checked after the prefixed name
+var envAliases = map[string][]string{
+	"log-level":  {"LOG_LEVEL"},
+	"log-format": {"LOG_HANDLER"},
+	"token":      {"GH_TOKEN", "GITHUB_TOKEN"},
+}
+
+// configGlobals are the global flags that can be set at the top level of
+// the config file; the directory, config path and token cannot be
+var configGlobals = []string{"log-level", "log-format", "output"}
+
+// Config is the content of the config file. Top level values are global
+// flags and maps are sections for each command, keyed by the command name:
+//
+//	log-level: debug
+//	semver:
+//	  default-bump: minor
+//	  prerelease-suffix-length: 10
+//	branch-name:
+//	  length: 20
+//	  reserved: [main, master]
+type Config struct {
+	Path     string                       // file the config was read from, empty when there is no file
+	Globals  map[string]string            // top level flag values
+	Sections map[string]map[string]string // flag values per command
+}
+
+// configValue converts a yaml value to the string form used by flags, with
+// lists joined by commas
+func configValue(v any) string {
+	if list, ok := v.([]any); ok {
+		strs := []string{}
+		for _, item := range list {
+			strs = append(strs, fmt.Sprint(item))
+		}
+		return strings.Join(strs, ",")
+	}
+	return fmt.Sprint(v)
+}
+
+// ConfigPath returns the path of the config file to use; the path passed
+// when set, otherwise the ConfigFile within the directory
+func ConfigPath(directory string, path string) string {
+	if path != "" {
+		return path
+	}
+	return filepath.Join(directory, ConfigFile)
+}
+
+// LoadConfig reads the config file at the path. A missing file is not an
+// error and returns an empty config.
+func LoadConfig(path string) (cfg *Config, err error) {
+	var (
+		content []byte
+		raw     = map[string]any{}
+	)
+	cfg = &Config{Globals: map[string]string{}, Sections: map[string]map[string]string{}}
+
+	if content, err = os.ReadFile(path); errors.Is(err, os.ErrNotExist) {
+		err = nil
+		return
+	} else if err != nil {
+		return
+	}
+	cfg.Path = path
+
+	if err = yaml.Unmarshal(content, &raw); err != nil {
+		err = fmt.Errorf(ErrConfigParse, path, err.Error())
+		return
+	}
+	for key, value := range raw {
+		switch v := value.(type) {
+		case map[string]any:
+			cfg.Sections[key] = map[string]string{}
+			for k, item := range v {
+				cfg.Sections[key][k] = configValue(item)
+			}
+		case nil:
+			err = fmt.Errorf(ErrConfigSection, path, key)
+			return
+		default:
+			cfg.Globals[key] = configValue(v)
+		}
+	}
+	return
+}
+
+// Unknown returns all keys in the config that do not match a flag, in the
+// form `section.key`. Unknown sections are reported by name.
+func (self *Config) Unknown(cmds []*Command) (unknown []string) {
+	unknown = []string{}
+	for key := range self.Globals {
+		if !slices.Contains(configGlobals, key) {
+			unknown = append(unknown, key)
+		}
+	}
+	for section, values := range self.Sections {
+		var cmd = find(cmds, section)
+		if cmd == nil {
+			unknown = append(unknown, section)
+			continue
+		}
+		fs := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+		cmd.Flags(fs)
+		for key := range values {
+			if fs.Lookup(key) == nil {
+				unknown = append(unknown, section+"."+key)
+			}
+		}
+	}
+	slices.Sort(unknown)
+	return
+}
+
+// EnvName returns the env value name for the flag
+func EnvName(flagName string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// InputName returns the env value name github uses for the action input
+func InputName(input string) string {
+	return InputPrefix + strings.ToUpper(strings.ReplaceAll(input, " ", "_"))
+}
+
+// inputValues returns the flag values from the INPUT_<NAME> env values of
+// the command inputs, keyed by flag name
+func inputValues(cmd *Command) (values map[string]string) {
+	values = map[string]string{}
+	for input, flagName := range cmd.Inputs {
+		if v := os.Getenv(InputName(input)); v != "" {
+			values[flagName] = v
+		}
+	}
+	return
+}
+
+// envValue returns the env value for the flag, checking the prefixed name and
+// then any aliases
+func envValue(flagName string) (value string, name string, ok bool) {
+	for _, name = range append([]string{EnvName(flagName)}, envAliases[flagName]...) {
+		if value = os.Getenv(name); value != "" {
+			ok = true
+			return
+		}
+	}
+	return
+}
+
+// visited returns the names of the flags set on the command line
+func visited(sets ...*flag.FlagSet) (set map[string]bool) {
+	set = map[string]bool{}
+	for _, fs := range sets {
+		fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	}
+	return
+}
+
+// applyValues sets the flags from the values, skipping any that have
+// already been set on the command line. Keys that are not flags are ignored.
+func applyValues(fs *flag.FlagSet, set map[string]bool, values map[string]string, from string) (err error) {
+	for key, value := range values {
+		if set[key] || fs.Lookup(key) == nil {
+			continue
+		}
+		if e := fs.Set(key, value); e != nil {
+			err = fmt.Errorf(ErrConfigValue, value, key, from, e.Error())
+			return
+		}
+	}
+	return
+}
+
+// applyDefaults sets flag values from the config file, the env and then the
+// action inputs, so the precedence order is:
+//
+//	flags > action inputs (INPUT_<NAME>) > env > config file > built-in defaults
+//
+// The directory and config path (which are needed to find the config file)
+// come from only the flags, inputs or env.
+//
+// The loaded config is returned so any problems can be logged.
+func applyDefaults(cmd *Command, fs *flag.FlagSet, root *flag.FlagSet, globals *Globals) (cfg *Config, err error) {
+	var (
+		set        = visited(root, fs)
+		fromEnv    = map[string]string{}
+		fromInputs = inputValues(cmd)
+		location   = map[string]string{}
+	)
+	fs.VisitAll(func(f *flag.Flag) {
+		if v, _, ok := envValue(f.Name); ok {
+			fromEnv[f.Name] = v
+		}
+	})
+	// find the config file using the directory from the flags or env
+	for _, key := range []string{"directory", "config"} {
+		if v, ok := fromEnv[key]; ok {
+			location[key] = v
+		}
+		if v, ok := fromInputs[key]; ok {
+			location[key] = v
+		}
+	}
+	if err = applyValues(fs, set, location, "env"); err != nil {
+		return
+	}
+	if cfg, err = LoadConfig(ConfigPath(globals.Directory, globals.Config)); err != nil {
+		return
+	}
+
+	globalValues := map[string]string{}
+	for _, key := range configGlobals {
+		if v, ok := cfg.Globals[key]; ok {
+			globalValues[key] = v
+		}
+	}
+	if err = applyValues(fs, set, globalValues, "config file"); err != nil {
+		return
+	}
+	if err = applyValues(fs, set, cfg.Sections[cmd.Name], "config file"); err != nil {
+		return
+	}
+	if err = applyValues(fs, set, fromEnv, "env"); err != nil {
+		return
+	}
+	err = applyValues(fs, set, fromInputs, "action inputs")
+	return
+}
+
+// invalidValues returns an error message for each value that cannot be set
+// on the flag set; keys without a flag are skipped
+func invalidValues(fs *flag.FlagSet, values map[string]string, path string) (invalid []string) {
+	invalid = []string{}
+	for key, value := range values {
+		if fs.Lookup(key) == nil {
+			continue
+		}
+		if e := fs.Set(key, value); e != nil {
+			invalid = append(invalid, fmt.Sprintf(ErrConfigValue, value, key, path, e.Error()))
+		}
+	}
+	return
+}
+
+// Invalid tries each value in the config against the flags and returns the
+// errors for those that cannot be set
+func (self *Config) Invalid(cmds []*Command) (invalid []string) {
+	var globals = flag.NewFlagSet(Name, flag.ContinueOnError)
+	GlobalFlags(globals, NewGlobals())
+	invalid = invalidValues(globals, self.Globals, self.Path)
+
+	for _, cmd := range cmds {
+		var fs = flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+		cmd.Flags(fs)
+		invalid = append(invalid, invalidValues(fs, self.Sections[cmd.Name], self.Path)...)
+	}
+	slices.Sort(invalid)
+	return
+}
+
+// ValidateConfig loads the config file and reports any keys that are not
+// known flags or values that are invalid for their flag
+//
+// Return example:
+//
+//	map[string]string{"path": ".github/opg-actions.yml", "valid": "true", "unknown": ""}
+func ValidateConfig(lg *slog.Logger, cmds []*Command, path string) (result map[string]string, err error) {
+	var (
+		cfg     *Config
+		unknown []string
+		invalid []string
+	)
+	lg = lg.With("operation", "ValidateConfig", "path", path)
+	result = map[string]string{"path": "", "valid": "false", "unknown": ""}
+
+	if cfg, err = LoadConfig(path); err != nil {
+		return
+	}
+	if cfg.Path == "" {
+		lg.Warn("no config file found.")
+		result["valid"] = "true"
+		return
+	}
+	result["path"] = cfg.Path
+
+	unknown = cfg.Unknown(cmds)
+	invalid = cfg.Invalid(cmds)
+	result["unknown"] = strings.Join(unknown, ",")
+
+	for _, msg := range invalid {
+		lg.Error(msg)
+	}
+	if len(unknown) > 0 {
+		err = fmt.Errorf(ErrConfigUnknown, cfg.Path, strings.Join(unknown, ", "))
+	} else if len(invalid) > 0 {
+		err = errors.New(invalid[0])
+	}
+	result["valid"] = fmt.Sprintf("%t", err == nil)
+	return
+}
+
+// ConfigCommand returns the `config` command, which has one action -
+// `validate` - to check the config file against the flags of the commands
+//
+//	opg-actions config validate --directory .
+func ConfigCommand(cmds []*Command) *Command {
+	return &Command{
+		Name:        "config",
+		Description: "Check the config file for unknown keys and invalid values (config validate).",
+		Flags: func(fs *flag.FlagSet) func(lg *slog.Logger, globals *Globals) (Result, error) {
+			return func(lg *slog.Logger, globals *Globals) (Result, error) {
+				if len(globals.Args) != 1 || globals.Args[0] != configValidate {
+					return nil, fmt.Errorf(ErrConfigAction, strings.Join(globals.Args, " "))
+				}
+				res, err := ValidateConfig(lg, cmds, ConfigPath(globals.Directory, globals.Config))
+				return Outputs(res), err
+			}
+		},
+	}
+}
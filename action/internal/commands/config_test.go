@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"opg-github-actions/action/internal/logger"
+)
+
+// writeConfig creates the config file within a new directory
+func writeConfig(t *testing.T, content string) (dir string) {
+	t.Helper()
+	dir = t.TempDir()
+	os.MkdirAll(filepath.Join(dir, ".github"), 0755)
+	os.WriteFile(filepath.Join(dir, ConfigFile), []byte(content), 0644)
+	return
+}
+
+type precedenceFixture struct {
+	Config        string
+	Env           map[string]string
+	Args          []string
+	ExpectedValue string
+	ExpectedLevel string
+}
+
+func TestCommandsConfigPrecedence(t *testing.T) {
+	var config = "log-level: warn\ntest:\n  value: from-config\n"
+	var fixtures = []precedenceFixture{
+		// built-in default
+		{Config: "", Args: []string{"test"}, ExpectedValue: "default", ExpectedLevel: "info"},
+		// config file over default
+		{Config: config, Args: []string{"test"}, ExpectedValue: "from-config", ExpectedLevel: "warn"},
+		// env over config file
+		{Config: config, Env: map[string]string{"OPG_ACTIONS_VALUE": "from-env", "LOG_LEVEL": "error"}, Args: []string{"test"}, ExpectedValue: "from-env", ExpectedLevel: "error"},
+		// action inputs over env
+		{Config: config, Env: map[string]string{"OPG_ACTIONS_VALUE": "from-env", "INPUT_TEST_VALUE": "from-input"}, Args: []string{"test"}, ExpectedValue: "from-input", ExpectedLevel: "warn"},
+		// empty inputs are ignored
+		{Config: config, Env: map[string]string{"OPG_ACTIONS_VALUE": "from-env", "INPUT_TEST_VALUE": ""}, Args: []string{"test"}, ExpectedValue: "from-env", ExpectedLevel: "warn"},
+		// flags over action inputs
+		{Config: config, Env: map[string]string{"INPUT_TEST_VALUE": "from-input"}, Args: []string{"test", "--value", "from-flag"}, ExpectedValue: "from-flag", ExpectedLevel: "warn"},
+		// flags over env
+		{Config: config, Env: map[string]string{"OPG_ACTIONS_VALUE": "from-env"}, Args: []string{"test", "--value", "from-flag", "--log-level", "debug"}, ExpectedValue: "from-flag", ExpectedLevel: "debug"},
+	}
+
+	for i, f := range fixtures {
+		var (
+			value   string
+			globals *Globals
+			dir     = writeConfig(t, f.Config)
+			cmds    = []*Command{testCommand(&value, &globals)}
+		)
+		t.Setenv("LOG_LEVEL", "")
+		for k, v := range f.Env {
+			t.Setenv(k, v)
+		}
+		code := Main(append([]string{"--directory", dir}, f.Args...), cmds)
+		if code != EXIT_OK {
+			t.Errorf("[%d] unexpected exit code [%d]", i, code)
+			continue
+		}
+		if value != f.ExpectedValue || globals.LogLevel != f.ExpectedLevel {
+			t.Errorf("[%d] expected [%s, %s] actual [%s, %s]", i, f.ExpectedValue, f.ExpectedLevel, value, globals.LogLevel)
+		}
+		for k := range f.Env {
+			os.Unsetenv(k)
+		}
+	}
+}
+
+type validateFixture struct {
+	Config          string
+	ErrorContains   string
+	ExpectedUnknown string
+}
+
+func TestCommandsConfigValidate(t *testing.T) {
+	var (
+		lg       = logger.New("error", "text")
+		value    string
+		globals  *Globals
+		cmds     = []*Command{testCommand(&value, &globals)}
+		fixtures = []validateFixture{
+			{Config: "log-level: warn\ntest:\n  value: x\n"},
+			{Config: "log-levle: warn\ntest:\n  valu: x\nother:\n  x: 1\n", ErrorContains: "unknown keys", ExpectedUnknown: "log-levle,other,test.valu"},
+			{Config: "token: abc\n", ErrorContains: "unknown keys", ExpectedUnknown: "token"},
+			{Config: "test: [1, 2\n", ErrorContains: "failed to parse"},
+		}
+	)
+
+	for i, f := range fixtures {
+		var dir = writeConfig(t, f.Config)
+		res, err := ValidateConfig(lg, cmds, ConfigPath(dir, ""))
+		if f.ErrorContains != "" {
+			if err == nil || !strings.Contains(err.Error(), f.ErrorContains) {
+				t.Errorf("[%d] expected error containing [%s], actual [%v]", i, f.ErrorContains, err)
+			}
+		} else if err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+		}
+		if res["unknown"] != f.ExpectedUnknown {
+			t.Errorf("[%d] unknown expected [%s] actual [%s]", i, f.ExpectedUnknown, res["unknown"])
+		}
+	}
+}
+
+func TestCommandsConfigInvalidValue(t *testing.T) {
+	var fs = flagSetWithInt()
+	var invalid = invalidValues(fs, map[string]string{"length": "abc", "other": "x"}, ConfigFile)
+	if len(invalid) != 1 || !strings.Contains(invalid[0], "[abc] for [length]") {
+		t.Errorf("expected one invalid value, actual [%v]", invalid)
+	}
+}
+
+// flagSetWithInt returns a flag set with a single int flag
+func flagSetWithInt() *flag.FlagSet {
+	var fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Int("length", 1, "")
+	return fs
+}
+
+func TestCommandsInputDirectory(t *testing.T) {
+	var (
+		value   string
+		globals *Globals
+		dir     = writeConfig(t, "test:\n  value: from-config\n")
+		cmds    = []*Command{testCommand(&value, &globals)}
+	)
+	// the config file is found using the directory from the action input
+	t.Setenv("INPUT_DIR", dir)
+	if code := Main([]string{"test"}, cmds); code != EXIT_OK {
+		t.Errorf("unexpected exit code [%d]", code)
+	}
+	if value != "from-config" || globals.Directory != dir {
+		t.Errorf("expected [from-config, %s] actual [%s, %s]", dir, value, globals.Directory)
+	}
+}
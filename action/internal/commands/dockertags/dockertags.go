@@ -0,0 +1,253 @@
+package dockertags
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"opg-github-actions/action/internal/branch"
+	"opg-github-actions/action/internal/commands"
+	"opg-github-actions/action/internal/repo"
+	"opg-github-actions/action/internal/semver"
+	"opg-github-actions/action/internal/strs"
+	"opg-github-actions/action/internal/tags"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+var ErrMissingVersion = errors.New("error: a semver version is required")
+
+const ErrInvalidVersion string = "error: version [%s] is not a valid semver"
+
+type Options struct {
+	Directory       string // directory of the git repo, used to find existing tags and the commit
+	Version         string // semver to generate tags for, like the tag output from semver
+	Hash            string // commit hash for the short sha tag, defaults to HEAD
+	Branch          string // branch name for the branch tag, when empty its found from the environment
+	Images          string // comma separated image names to prefix the tags with
+	Major           bool   // add a floating major tag (v1) for releases
+	Minor           bool   // add a floating minor tag (v1.4) for releases
+	Latest          bool   // add `latest` when this is the highest release
+	ShortHash       bool   // add the short commit hash
+	ShortHashLength int    // length of the short commit hash
+	BranchTag       bool   // add the branch name
+	WithoutPrefix   bool   // remove the prefix from version tags
+	Prefix          string // custom tag prefix (like chart/) of the version and existing tags, empty uses the default single letter prefix
+}
+
+// newRunOptions returns the default options
+func newRunOptions() *Options {
+	return &Options{
+		Directory:       "",
+		Version:         "",
+		Hash:            "",
+		Branch:          "",
+		Images:          "",
+		Major:           true,
+		Minor:           true,
+		Latest:          true,
+		ShortHash:       true,
+		ShortHashLength: 7,
+		BranchTag:       true,
+		WithoutPrefix:   false,
+		Prefix:          "",
+	}
+}
+
+// Result contains the generated docker tags
+type Result struct {
+	Version string   `json:"version"` // version the tags were generated from
+	Tags    []string `json:"tags"`    // docker tags, most specific first
+	Images  []string `json:"images"`  // each image name with each tag (image:tag)
+	Latest  bool     `json:"latest"`  // if this version was tagged as latest
+}
+
+// Outputs returns the string values used for github outputs
+func (self *Result) Outputs() (outputs map[string]string) {
+	outputs = map[string]string{}
+	if self == nil {
+		return
+	}
+	outputs = map[string]string{
+		"version": self.Version,
+		"tags":    strings.Join(self.Tags, ","),
+		"images":  strings.Join(self.Images, ","),
+		"latest":  strconv.FormatBool(self.Latest),
+	}
+	return
+}
+
+// highest checks that no existing release that matches is greater than
+// the version
+func highest(version *semver.Semver, existing []*semver.Semver, match func(s *semver.Semver) bool) bool {
+	for _, s := range semver.GetReleases(existing) {
+		if match(s) && semver.Compare(s, version) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Tags returns the docker tags for the version, in order of most specific
+// first:
+//
+//   - the full version (v1.4.2 or v1.4.2-branch.1)
+//   - floating minor (v1.4) and major (v1) tags, for releases that are the
+//     highest within that minor or major line
+//   - latest, for releases that are the highest overall
+//   - the short commit hash
+//   - the branch name
+//
+// Each tag is sanitised to the docker tag grammar (see strs.DockerTag) and
+// duplicates are removed.
+func Tags(lg *slog.Logger, version *semver.Semver, existing []*semver.Semver, options *Options) (list []string, latest bool) {
+	var (
+		prefix = version.Prefix
+		add    = func(tag string) {
+			if tag = strs.DockerTag(tag); tag != "" && !slices.Contains(list, tag) {
+				list = append(list, tag)
+			}
+		}
+	)
+	lg = lg.With("operation", "Tags", "version", version.String())
+	list = []string{}
+
+	if options.WithoutPrefix {
+		prefix = ""
+	}
+	add(prefix + version.Stringy(false))
+
+	if version.IsRelease() {
+		sameMinor := func(s *semver.Semver) bool { return s.Major == version.Major && s.Minor == version.Minor }
+		sameMajor := func(s *semver.Semver) bool { return s.Major == version.Major }
+
+		if options.Minor && highest(version, existing, sameMinor) {
+			add(fmt.Sprintf("%s%s.%s", prefix, version.Major, version.Minor))
+		}
+		if options.Major && highest(version, existing, sameMajor) {
+			add(prefix + version.Major)
+		}
+		if options.Latest && semver.IsLatest(lg, existing, version) {
+			latest = true
+			add("latest")
+		}
+	}
+	if options.ShortHash && options.Hash != "" {
+		add(strs.Truncate(options.Hash, options.ShortHashLength))
+	}
+	if options.BranchTag && options.Branch != "" {
+		add(options.Branch)
+	}
+	lg.Debug("generated tags ... ", "tags", list)
+	return
+}
+
+// Run generates the docker tags for the version, using the existing semver
+// tags in the repository to decide which floating tags should move.
+func Run(lg *slog.Logger, options *Options) (result *Result, err error) {
+	var (
+		repository *git.Repository
+		gittags    []*plumbing.Reference
+		existing   []*semver.Semver
+		head       *plumbing.Reference
+		version    *semver.Semver
+		scheme     = &semver.Semantic{Prefix: options.Prefix}
+	)
+	lg = lg.With("operation", "dockertags.Run", "version", options.Version)
+
+	if options.Version == "" {
+		err = ErrMissingVersion
+		return
+	}
+	if !semver.ValidPrefix(options.Prefix) {
+		err = fmt.Errorf(semver.ErrInvalidPrefix, options.Prefix)
+		return
+	}
+	if version = scheme.Parse(options.Version); version == nil {
+		err = fmt.Errorf(ErrInvalidVersion, options.Version)
+		return
+	}
+	if repository, err = repo.FromDir(options.Directory); err != nil {
+		return
+	}
+	if gittags, err = tags.All(lg, repository); err != nil {
+		return
+	}
+	if existing, err = semver.FromGitRefsWithScheme(scheme, gittags); err != nil {
+		return
+	}
+	if options.Hash == "" {
+		if head, err = repository.Head(); err != nil {
+			return
+		}
+		options.Hash = head.Hash().String()
+	}
+	// when no branch name is passed, look for it in the environment
+	if options.Branch == "" && options.BranchTag {
+		if resolved, e := branch.Resolve(""); e == nil {
+			options.Branch = resolved.Name
+		}
+	}
+
+	result = &Result{Version: options.Version, Images: []string{}}
+	result.Tags, result.Latest = Tags(lg, version, existing, options)
+
+	for _, image := range strings.Split(options.Images, ",") {
+		if image = strings.TrimSpace(image); image == "" {
+			continue
+		}
+		for _, tag := range result.Tags {
+			result.Images = append(result.Images, image+":"+tag)
+		}
+	}
+	return
+}
+
+// Command returns the docker-tags command, with the flags registered
+// against its own options. The repository directory comes from the global
+// flags.
+func Command() *commands.Command {
+	return &commands.Command{
+		Name:        "docker-tags",
+		Description: "Generate the docker image tags for a semver.",
+		Inputs: map[string]string{
+			"version":           "version",
+			"hash":              "hash",
+			"branch_name":       "branch",
+			"images":            "images",
+			"major":             "major",
+			"minor":             "minor",
+			"latest":            "latest",
+			"short_hash":        "short-hash",
+			"short_hash_length": "short-hash-length",
+			"branch_tag":        "branch-tag",
+			"without_prefix":    "without-prefix",
+			"prefix":            "prefix",
+		},
+		Flags: func(fs *flag.FlagSet) func(lg *slog.Logger, globals *commands.Globals) (commands.Result, error) {
+			var options = newRunOptions()
+
+			fs.StringVar(&options.Version, "version", options.Version, "Semver to generate tags for, like the tag from semver.")
+			fs.StringVar(&options.Hash, "hash", options.Hash, "Commit hash for the short hash tag. Defaults to HEAD.")
+			fs.StringVar(&options.Branch, "branch", options.Branch, "Branch name to tag with. When empty, GITHUB_HEAD_REF and then GITHUB_REF are used.")
+			fs.StringVar(&options.Images, "images", options.Images, "Comma separated image names; when set the images output contains each image with each tag.")
+			fs.BoolVar(&options.Major, "major", options.Major, "Add a floating major tag (v1) for releases that are the highest in that major version.")
+			fs.BoolVar(&options.Minor, "minor", options.Minor, "Add a floating minor tag (v1.4) for releases that are the highest in that minor version.")
+			fs.BoolVar(&options.Latest, "latest", options.Latest, "Add the latest tag for the highest release.")
+			fs.BoolVar(&options.ShortHash, "short-hash", options.ShortHash, "Add the short commit hash as a tag.")
+			fs.IntVar(&options.ShortHashLength, "short-hash-length", options.ShortHashLength, "Length of the short commit hash.")
+			fs.BoolVar(&options.BranchTag, "branch-tag", options.BranchTag, "Add the branch name as a tag.")
+			fs.BoolVar(&options.WithoutPrefix, "without-prefix", options.WithoutPrefix, "Remove the prefix from version tags.")
+			fs.StringVar(&options.Prefix, "prefix", options.Prefix, "Custom tag prefix of the version and existing tags, like release- or chart/. When empty, tags with an optional single letter prefix are used.")
+
+			return func(lg *slog.Logger, globals *commands.Globals) (commands.Result, error) {
+				options.Directory = globals.Directory
+				return Run(lg, options)
+			}
+		},
+	}
+}
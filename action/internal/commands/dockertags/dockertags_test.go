@@ -0,0 +1,132 @@
+package dockertags
+
+import (
+	"opg-github-actions/action/internal/logger"
+	"opg-github-actions/action/internal/semver"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+type tagsFixture struct {
+	Version  string
+	Existing []string
+	Options  func(o *Options)
+	Expected []string
+	Latest   bool
+}
+
+func TestDockerTags(t *testing.T) {
+	var (
+		lg       = logger.New("error", "text")
+		existing = []string{"v1.3.0", "v1.4.1", "v2.0.0-beta.0", "v1.4.2"}
+	)
+	var tests = []*tagsFixture{
+		// highest release gets all floating tags
+		{
+			Version: "v1.4.2", Existing: existing, Latest: true,
+			Expected: []string{"v1.4.2", "v1.4", "v1", "latest", "abcdef0", "feature-new-thing"},
+		},
+		// first release of a new major
+		{
+			Version: "v2.0.0", Existing: existing, Latest: true,
+			Expected: []string{"v2.0.0", "v2.0", "v2", "latest", "abcdef0", "feature-new-thing"},
+		},
+		// hotfix of an older minor only moves the minor tag
+		{
+			Version: "v1.3.1", Existing: existing,
+			Expected: []string{"v1.3.1", "v1.3", "abcdef0", "feature-new-thing"},
+		},
+		// hotfix of an older major moves minor and major, but not latest
+		{
+			Version: "v1.5.0", Existing: append(existing, "v2.0.0"),
+			Expected: []string{"v1.5.0", "v1.5", "v1", "abcdef0", "feature-new-thing"},
+		},
+		// prereleases never get floating tags and build metadata is sanitised
+		{
+			Version: "v1.5.0-feature.1+build.7", Existing: existing,
+			Expected: []string{"v1.5.0-feature.1-build.7", "abcdef0", "feature-new-thing"},
+		},
+		// floating tags can be disabled and the prefix removed
+		{
+			Version: "v1.4.2", Existing: existing, Options: func(o *Options) { o.Major, o.Latest, o.WithoutPrefix = false, false, true },
+			Expected: []string{"1.4.2", "1.4", "abcdef0", "feature-new-thing"},
+		},
+	}
+
+	for i, test := range tests {
+		var options = newRunOptions()
+		options.Hash = "abcdef0123456789"
+		options.Branch = "feature/new-thing"
+		if test.Options != nil {
+			test.Options(options)
+		}
+		semvers, _ := semver.FromStrings(test.Existing...)
+
+		actual, lat := Tags(lg, semver.FromString(test.Version), semvers, options)
+		if !slices.Equal(actual, test.Expected) {
+			t.Errorf("[%d] expected [%v], actual [%v]", i, test.Expected, actual)
+		}
+		if lat != test.Latest {
+			t.Errorf("[%d] expected latest [%t], actual [%t]", i, test.Latest, lat)
+		}
+	}
+}
+
+func TestDockerTagsRun(t *testing.T) {
+	var (
+		lg  = logger.New("error", "text")
+		dir = t.TempDir()
+	)
+	r, _ := git.PlainInit(dir, false)
+	w, _ := r.Worktree()
+	hash, _ := w.Commit("initial commit", &git.CommitOptions{AllowEmptyCommits: true, Author: &object.Signature{Name: "go test", Email: "test@example.com"}})
+	r.CreateTag("v1.0.0", hash, nil)
+	r.CreateTag("v1.1.0", hash, nil)
+
+	var options = newRunOptions()
+	options.Directory = dir
+	options.Version = "v1.0.1"
+	options.Branch = "main"
+	options.Images = "ghcr.io/org/app, app"
+
+	res, err := Run(lg, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	actual := res.Outputs()
+	if expected := "v1.0.1,v1.0," + hash.String()[:7] + ",main"; actual["tags"] != expected {
+		t.Errorf("expected tags [%s], actual [%s]", expected, actual["tags"])
+	}
+	if !strings.HasPrefix(actual["images"], "ghcr.io/org/app:v1.0.1,") || !strings.HasSuffix(actual["images"], ",app:main") {
+		t.Errorf("expected images for each tag, actual [%s]", actual["images"])
+	}
+	if actual["latest"] != "false" {
+		t.Errorf("expected latest to be false, actual [%s]", actual["latest"])
+	}
+
+	options.Version = "not-a-version"
+	if _, err = Run(lg, options); err == nil || !strings.Contains(err.Error(), "not a valid semver") {
+		t.Errorf("expected invalid version error, actual [%v]", err)
+	}
+	// custom prefixes read the version and existing tags with that prefix
+	r.CreateTag("chart/1.0.0", hash, nil)
+	r.CreateTag("chart/1.1.0", hash, nil)
+	options.Version = "chart/1.1.1"
+	options.Prefix = "chart/"
+	options.Images = ""
+	if res, err = Run(lg, options); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if expected := "chart-1.1.1,chart-1.1,chart-1,latest," + hash.String()[:7] + ",main"; res.Outputs()["tags"] != expected {
+		t.Errorf("expected tags [%s], actual [%s]", expected, res.Outputs()["tags"])
+	}
+
+	options.Prefix = "chart1"
+	if _, err = Run(lg, options); err == nil || !strings.Contains(err.Error(), "is not valid") {
+		t.Errorf("expected invalid prefix error, actual [%v]", err)
+	}
+}
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"opg-github-actions/action/internal/logger"
+)
+
+// OutputVersion is the version of the json output document, this should be
+// increased when a change would break existing consumers
+const OutputVersion int = 1
+
+const ErrOutputFormat string = "error: unknown output format [%s], expected text or json"
+
+// Output formats
+const (
+	OUTPUT_TEXT string = "text" // key=value lines
+	OUTPUT_JSON string = "json" // a single json Document
+)
+
+// Result is returned by each command. The typed fields are used for json
+// output, while Outputs returns the string values used for github outputs
+// and text output.
+type Result interface {
+	Outputs() map[string]string
+}
+
+// Outputs is a Result for commands that only have string values
+type Outputs map[string]string
+
+// Outputs returns the values as they are
+func (self Outputs) Outputs() map[string]string {
+	return self
+}
+
+// Document is the json output of a command
+type Document struct {
+	Version int    `json:"version"`          // see OutputVersion
+	Command string `json:"command"`          // name of the command that was run
+	Result  Result `json:"result,omitempty"` // typed result of the command
+	Error   string `json:"error,omitempty"`  // error message when the command failed
+}
+
+// validOutput checks the output format is known
+func validOutput(format string) (err error) {
+	if format != OUTPUT_TEXT && format != OUTPUT_JSON {
+		err = fmt.Errorf(ErrOutputFormat, format)
+	}
+	return
+}
+
+// writeJSON outputs the result (or error) as a json document
+func writeJSON(w io.Writer, command string, res Result, err error) error {
+	var doc = &Document{Version: OutputVersion, Command: command}
+	if err != nil {
+		doc.Error = err.Error()
+	} else {
+		doc.Result = res
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// output handles the result of the command; in text mode the values are
+// printed as key=value lines, for json a Document is written. In both cases
+// the values are written to GITHUB_OUTPUT when within github.
+func output(lg *slog.Logger, w io.Writer, format string, command string, res Result, err error) {
+	if format == OUTPUT_JSON {
+		writeJSON(w, command, res, err)
+		if err == nil {
+			logger.Outputs(lg, res.Outputs())
+		}
+		return
+	}
+	if err == nil {
+		logger.Result(lg, res.Outputs())
+	}
+}
@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type typedResult struct {
+	Created bool     `json:"created"`
+	Tags    []string `json:"tags"`
+}
+
+func (self *typedResult) Outputs() map[string]string {
+	return map[string]string{"created": "true"}
+}
+
+func TestCommandsOutputJSON(t *testing.T) {
+	var (
+		buf    = new(bytes.Buffer)
+		actual = map[string]any{}
+	)
+	writeJSON(buf, "test", &typedResult{Created: true, Tags: []string{"a", "b"}}, nil)
+	json.Unmarshal(buf.Bytes(), &actual)
+
+	if actual["version"] != float64(OutputVersion) || actual["command"] != "test" {
+		t.Errorf("expected version [%d] and command [test], actual [%v]", OutputVersion, actual)
+	}
+	result, _ := actual["result"].(map[string]any)
+	if result["created"] != true || len(result["tags"].([]any)) != 2 {
+		t.Errorf("expected typed values, actual [%v]", result)
+	}
+	if _, ok := actual["error"]; ok {
+		t.Errorf("did not expect an error, actual [%v]", actual["error"])
+	}
+
+	buf.Reset()
+	actual = map[string]any{}
+	writeJSON(buf, "test", nil, errors.New("failed"))
+	json.Unmarshal(buf.Bytes(), &actual)
+	if actual["error"] != "failed" || actual["result"] != nil {
+		t.Errorf("expected error document, actual [%v]", actual)
+	}
+}
+
+func TestCommandsOutputFormat(t *testing.T) {
+	var (
+		value   string
+		globals *Globals
+		cmds    = []*Command{testCommand(&value, &globals)}
+	)
+	t.Setenv("LOG_LEVEL", "error")
+	if code := Main([]string{"test", "--output", "yaml"}, cmds); code != EXIT_USAGE {
+		t.Errorf("expected exit code [%d] for unknown format, actual [%d]", EXIT_USAGE, code)
+	}
+	if code := Main([]string{"test", "--output", "json", "--value", "fail"}, cmds); code != EXIT_ERROR {
+		t.Errorf("expected exit code [%d] for failed command, actual [%d]", EXIT_ERROR, code)
+	}
+}
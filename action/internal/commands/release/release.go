@@ -0,0 +1,205 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"opg-github-actions/action/internal/commands"
+	"opg-github-actions/action/internal/releases"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v74/github"
+)
+
+var ErrMissingTag = errors.New("error: a tag is required to create a release")
+
+type Options struct {
+	Directory  string // directory artifact patterns are relative to
+	Repository string // owner/name of the repository, defaults to GITHUB_REPOSITORY
+	APIURL     string // github api url, defaults to GITHUB_API_URL
+	UploadURL  string // github upload url for assets, found from the api url when empty
+	Token      string // github token used for the api calls
+	Tag        string // tag to find or create the release for
+	Prerelease bool   // flag the release as a prerelease
+	Latest     string // when set (true / false) overrides if this is the latest release
+	Notes      string // how to generate the release notes (--notes-from-tag or --generate-notes)
+	Artifacts  string // whitespace separated glob patterns of files to attach
+}
+
+// newRunOptions returns the default options, using the github environment
+// values for the repository and api
+func newRunOptions() *Options {
+	var apiURL = os.Getenv("GITHUB_API_URL")
+	if apiURL == "" {
+		apiURL = releases.DefaultAPIURL
+	}
+	return &Options{
+		Directory:  "",
+		Repository: os.Getenv("GITHUB_REPOSITORY"),
+		APIURL:     apiURL,
+		UploadURL:  "",
+		Token:      "",
+		Tag:        "",
+		Prerelease: false,
+		Latest:     "",
+		Notes:      string(releases.NOTES_FROM_TAG),
+		Artifacts:  "",
+	}
+}
+
+// Result contains the release details
+type Result struct {
+	Tag        string   `json:"tag"`        // tag the release is for
+	URL        string   `json:"url"`        // link to the release
+	ID         int64    `json:"id"`         // github id of the release
+	Prerelease bool     `json:"prerelease"` // if the release is a prerelease
+	Created    bool     `json:"created"`    // false when the release already existed
+	Assets     []string `json:"assets"`     // names of the artifacts uploaded
+}
+
+// Outputs returns the string values used for github outputs
+func (self *Result) Outputs() (outputs map[string]string) {
+	outputs = map[string]string{}
+	if self == nil {
+		return
+	}
+	outputs = map[string]string{
+		"tag":        self.Tag,
+		"url":        self.URL,
+		"id":         strconv.FormatInt(self.ID, 10),
+		"prerelease": strconv.FormatBool(self.Prerelease),
+		"created":    strconv.FormatBool(self.Created),
+		"assets":     strings.Join(self.Assets, ","),
+	}
+	return
+}
+
+// newResult converts the github release into a result
+func newResult(release *github.RepositoryRelease, created bool, assets []string) *Result {
+	return &Result{
+		Tag:        release.GetTagName(),
+		URL:        release.GetHTMLURL(),
+		ID:         release.GetID(),
+		Prerelease: release.GetPrerelease(),
+		Created:    created,
+		Assets:     assets,
+	}
+}
+
+// Run finds the existing release for the tag, or creates it when there is
+// not one.
+//
+// Creating the release follows the same rules as the action did:
+//   - the tag must already exist in the remote repository
+//   - the release name is the tag
+//   - latest is false for prereleases, unless overwritten by options.Latest
+//   - an existing draft release for the tag is reused
+//   - artifacts are only uploaded when the release is created
+func Run(lg *slog.Logger, options *Options) (result *Result, err error) {
+	var (
+		ctx        = context.Background()
+		client     *github.Client
+		repository *releases.Repository
+		release    *github.RepositoryRelease
+		makeLatest string
+		body       string
+		files      []string
+		uploaded   []string
+	)
+	lg = lg.With("operation", "release.Run", "tag", options.Tag, "repository", options.Repository)
+
+	if options.Tag == "" {
+		err = ErrMissingTag
+		return
+	}
+	if err = releases.ValidNotes(releases.Notes(options.Notes)); err != nil {
+		return
+	}
+	if makeLatest, err = releases.MakeLatest(options.Latest, options.Prerelease); err != nil {
+		return
+	}
+	if repository, err = releases.ParseRepository(options.Repository); err != nil {
+		return
+	}
+	if client, err = releases.NewClient(options.Token, options.APIURL, options.UploadURL); err != nil {
+		return
+	}
+
+	lg.Info("looking for existing release ... ")
+	if release, err = releases.Find(ctx, lg, client, repository, options.Tag, options.Prerelease); err != nil {
+		return
+	}
+	if release != nil {
+		lg.Info("release already exists, skipping ... ", "url", release.GetHTMLURL())
+		result = newResult(release, false, []string{})
+		return
+	}
+
+	// check the tag exists and fetch its message before creating anything
+	if body, err = releases.TagMessage(ctx, lg, client, repository, options.Tag); err != nil {
+		return
+	}
+	if files, err = releases.Artifacts(options.Directory, options.Artifacts); err != nil {
+		return
+	}
+
+	lg.Info("creating release ... ", "prerelease", options.Prerelease, "latest", makeLatest)
+	release = &github.RepositoryRelease{
+		TagName:    github.Ptr(options.Tag),
+		Name:       github.Ptr(options.Tag),
+		Prerelease: github.Ptr(options.Prerelease),
+		MakeLatest: github.Ptr(makeLatest),
+	}
+	if releases.Notes(options.Notes) == releases.NOTES_GENERATE {
+		release.GenerateReleaseNotes = github.Ptr(true)
+	} else {
+		release.Body = github.Ptr(body)
+	}
+	if release, _, err = client.Repositories.CreateRelease(ctx, repository.Owner, repository.Name, release); err != nil {
+		return
+	}
+
+	if uploaded, err = releases.Upload(ctx, lg, client, repository, release, files); err != nil {
+		return
+	}
+	result = newResult(release, true, uploaded)
+	return
+}
+
+// Command returns the release command, with the flags registered against
+// its own options. The directory and token come from the global flags.
+func Command() *commands.Command {
+	return &commands.Command{
+		Name:        "release",
+		Description: "Find or create a github release for a tag.",
+		Inputs: map[string]string{
+			"tag":                "tag",
+			"github_token":       "token",
+			"prerelease":         "prerelease",
+			"latest":             "latest",
+			"release_artifact":   "artifacts",
+			"release_notes_flag": "notes",
+		},
+		Flags: func(fs *flag.FlagSet) func(lg *slog.Logger, globals *commands.Globals) (commands.Result, error) {
+			var options = newRunOptions()
+
+			fs.StringVar(&options.Tag, "tag", options.Tag, "Git tag to find or create the release for.")
+			fs.BoolVar(&options.Prerelease, "prerelease", options.Prerelease, "Flag the release as a prerelease. An existing release must also be a prerelease.")
+			fs.StringVar(&options.Latest, "latest", options.Latest, "Set to true or false to overwrite if this is the latest release. When empty, only full releases are latest.")
+			fs.StringVar(&options.Notes, "notes", options.Notes, "How to generate the release notes; --notes-from-tag or --generate-notes.")
+			fs.StringVar(&options.Artifacts, "artifacts", options.Artifacts, "Space separated glob patterns of files to attach to a new release, relative to the directory.")
+			fs.StringVar(&options.Repository, "repository", options.Repository, "Repository (owner/name) to create the release in. Defaults to GITHUB_REPOSITORY.")
+			fs.StringVar(&options.APIURL, "api-url", options.APIURL, "GitHub api url. Defaults to GITHUB_API_URL.")
+			fs.StringVar(&options.UploadURL, "upload-url", options.UploadURL, "GitHub upload url for artifacts. When empty, it is found from the api url (uploads.github.com for github.com).")
+
+			return func(lg *slog.Logger, globals *commands.Globals) (commands.Result, error) {
+				options.Directory = globals.Directory
+				options.Token = globals.Token
+				return Run(lg, options)
+			}
+		},
+	}
+}
@@ -0,0 +1,204 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"opg-github-actions/action/internal/logger"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v74/github"
+)
+
+// testTag is a tag within the stand in api, annotated tags have a message
+type testTag struct {
+	Annotated bool
+	Message   string
+}
+
+// testAPI is a stand in for the parts of the github api used to find and
+// create releases, recording what was created. Drafts are only returned by
+// the release list, as with github.
+type testAPI struct {
+	Tags     map[string]*testTag
+	Releases map[string]*github.RepositoryRelease
+	Drafts   []*github.RepositoryRelease
+	Created  *github.RepositoryRelease
+	Uploaded []string
+}
+
+// server returns the stand in api, using the github enterprise server path
+func (self *testAPI) server(t *testing.T) *httptest.Server {
+	t.Helper()
+	var (
+		mux  = http.NewServeMux()
+		base = "/api/v3/repos/owner/repo"
+	)
+	mux.HandleFunc("GET "+base+"/releases/tags/{tag}", func(w http.ResponseWriter, r *http.Request) {
+		if rel, ok := self.Releases[r.PathValue("tag")]; ok {
+			json.NewEncoder(w).Encode(rel)
+			return
+		}
+		http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+	})
+	mux.HandleFunc("GET "+base+"/releases", func(w http.ResponseWriter, r *http.Request) {
+		var all = append([]*github.RepositoryRelease{}, self.Drafts...)
+		for _, rel := range self.Releases {
+			all = append(all, rel)
+		}
+		json.NewEncoder(w).Encode(all)
+	})
+	mux.HandleFunc("GET "+base+"/git/ref/tags/{tag}", func(w http.ResponseWriter, r *http.Request) {
+		var tag, ok = self.Tags[r.PathValue("tag")]
+		if !ok {
+			http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+			return
+		}
+		var objectType = "commit"
+		if tag.Annotated {
+			objectType = "tag"
+		}
+		fmt.Fprintf(w, `{"ref":"refs/tags/%s","object":{"type":"%s","sha":"%s"}}`, r.PathValue("tag"), objectType, r.PathValue("tag"))
+	})
+	mux.HandleFunc("GET "+base+"/git/tags/{sha}", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&github.Tag{Message: github.Ptr(self.Tags[r.PathValue("sha")].Message)})
+	})
+	mux.HandleFunc("POST "+base+"/releases", func(w http.ResponseWriter, r *http.Request) {
+		self.Created = &github.RepositoryRelease{}
+		json.NewDecoder(r.Body).Decode(self.Created)
+		self.Created.ID = github.Ptr(int64(10))
+		self.Created.HTMLURL = github.Ptr("https://github.com/owner/repo/releases/tag/" + self.Created.GetTagName())
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(self.Created)
+	})
+	return httptest.NewServer(mux)
+}
+
+// uploads returns a separate stand in for the upload api, as github uses a
+// different host for uploading release assets
+func (self *testAPI) uploads(t *testing.T) *httptest.Server {
+	t.Helper()
+	var mux = http.NewServeMux()
+	mux.HandleFunc("POST /api/uploads/repos/owner/repo/releases/{id}/assets", func(w http.ResponseWriter, r *http.Request) {
+		self.Uploaded = append(self.Uploaded, r.URL.Query().Get("name"))
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{}`)
+	})
+	return httptest.NewServer(mux)
+}
+
+type releaseFixture struct {
+	Options       *Options
+	Created       bool
+	Body          string
+	Generate      bool
+	Latest        string
+	Uploaded      []string
+	ErrorContains string
+}
+
+func TestReleaseRun(t *testing.T) {
+	var (
+		lg  = logger.New("error", "text")
+		dir = t.TempDir()
+	)
+	os.WriteFile(filepath.Join(dir, "build.zip"), []byte("zip"), 0644)
+
+	var tests = []*releaseFixture{
+		// existing full release is found
+		{Options: &Options{Tag: "v1.0.0"}, Created: false},
+		// existing full release does not count when looking for a prerelease
+		{Options: &Options{Tag: "v1.0.0", Prerelease: true}, Created: true, Body: "v1", Latest: "false"},
+		// existing prerelease is found
+		{Options: &Options{Tag: "v1.1.0-beta.0", Prerelease: true}, Created: false},
+		// existing draft is reused
+		{Options: &Options{Tag: "v3.0.0"}, Created: false},
+		// new release from an annotated tag
+		{Options: &Options{Tag: "v2.0.0"}, Created: true, Body: "release notes", Latest: "true"},
+		// latest can be overwritten
+		{Options: &Options{Tag: "v2.0.0", Prerelease: true, Latest: "true"}, Created: true, Body: "release notes", Latest: "true"},
+		// generated notes with artifacts
+		{Options: &Options{Tag: "v2.0.0", Notes: "--generate-notes", Artifacts: "*.zip"}, Created: true, Generate: true, Latest: "true", Uploaded: []string{"build.zip"}},
+		// errors
+		{Options: &Options{Tag: "v9.9.9"}, ErrorContains: "does not exist in the remote"},
+		{Options: &Options{Tag: "v2.0.0", Artifacts: "*.tar"}, ErrorContains: "no artifacts match"},
+		{Options: &Options{Tag: "v2.0.0", Notes: "--notes"}, ErrorContains: "should be one of"},
+		{Options: &Options{Tag: ""}, ErrorContains: ErrMissingTag.Error()},
+	}
+
+	for i, test := range tests {
+		var api = &testAPI{
+			Tags: map[string]*testTag{
+				"v1.0.0":        {Annotated: true, Message: "v1"},
+				"v1.1.0-beta.0": {},
+				"v2.0.0":        {Annotated: true, Message: "release notes"},
+			},
+			Releases: map[string]*github.RepositoryRelease{
+				"v1.0.0":        {ID: github.Ptr(int64(1)), TagName: github.Ptr("v1.0.0"), HTMLURL: github.Ptr("https://github.com/owner/repo/releases/tag/v1.0.0")},
+				"v1.1.0-beta.0": {ID: github.Ptr(int64(2)), TagName: github.Ptr("v1.1.0-beta.0"), Prerelease: github.Ptr(true), HTMLURL: github.Ptr("https://github.com/owner/repo/releases/tag/v1.1.0-beta.0")},
+			},
+			Drafts: []*github.RepositoryRelease{
+				{ID: github.Ptr(int64(3)), TagName: github.Ptr("v3.0.0"), Draft: github.Ptr(true), HTMLURL: github.Ptr("https://github.com/owner/repo/releases/tag/v3.0.0")},
+			},
+		}
+		var server, uploads = api.server(t), api.uploads(t)
+		var options = newRunOptions()
+		options.Directory = dir
+		options.Repository = "owner/repo"
+		options.APIURL = server.URL
+		options.UploadURL = uploads.URL
+		options.Tag = test.Options.Tag
+		options.Prerelease = test.Options.Prerelease
+		options.Latest = test.Options.Latest
+		options.Artifacts = test.Options.Artifacts
+		if test.Options.Notes != "" {
+			options.Notes = test.Options.Notes
+		}
+
+		res, err := Run(lg, options)
+		server.Close()
+		uploads.Close()
+
+		if test.ErrorContains != "" {
+			if err == nil || !strings.Contains(err.Error(), test.ErrorContains) {
+				t.Errorf("[%d] expected error containing [%s], actual [%v]", i, test.ErrorContains, err)
+			}
+			if api.Created != nil {
+				t.Errorf("[%d] release should not have been created on error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+			continue
+		}
+
+		actual := res.Outputs()
+		if expected := "https://github.com/owner/repo/releases/tag/" + test.Options.Tag; actual["url"] != expected {
+			t.Errorf("[%d] expected url [%s], actual [%s]", i, expected, actual["url"])
+		}
+		if res.Created != test.Created || (api.Created != nil) != test.Created {
+			t.Errorf("[%d] expected created [%t], actual [%t]", i, test.Created, res.Created)
+		}
+		if !test.Created {
+			continue
+		}
+		if api.Created.GetName() != test.Options.Tag || api.Created.GetPrerelease() != test.Options.Prerelease {
+			t.Errorf("[%d] release name or prerelease flag mismatch: [%s] [%t]", i, api.Created.GetName(), api.Created.GetPrerelease())
+		}
+		if api.Created.GetMakeLatest() != test.Latest {
+			t.Errorf("[%d] expected latest [%s], actual [%s]", i, test.Latest, api.Created.GetMakeLatest())
+		}
+		if api.Created.GetBody() != test.Body || api.Created.GetGenerateReleaseNotes() != test.Generate {
+			t.Errorf("[%d] expected body [%s] generate [%t], actual [%s] [%t]", i, test.Body, test.Generate, api.Created.GetBody(), api.Created.GetGenerateReleaseNotes())
+		}
+		if !slices.Equal(api.Uploaded, test.Uploaded) || actual["assets"] != strings.Join(test.Uploaded, ",") {
+			t.Errorf("[%d] expected uploads [%v], actual [%v] [%s]", i, test.Uploaded, api.Uploaded, actual["assets"])
+		}
+	}
+}
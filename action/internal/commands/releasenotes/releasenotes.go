@@ -0,0 +1,220 @@
+package releasenotes
+
+import (
+	"flag"
+	"log/slog"
+	"opg-github-actions/action/internal/commands"
+	"opg-github-actions/action/internal/commits"
+	"opg-github-actions/action/internal/notes"
+	"opg-github-actions/action/internal/repo"
+	"opg-github-actions/action/internal/semver"
+	"opg-github-actions/action/internal/tags"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+const defaultServerURL string = "https://github.com"
+
+type Options struct {
+	Directory  string // directory where the git repo is
+	From       string // tag or reference to start from, defaults to the previous release
+	To         string // tag or reference to finish at, defaults to HEAD
+	Version    string // heading for the notes, defaults to To
+	Repository string // owner/name used for links, defaults to GITHUB_REPOSITORY
+	ServerURL  string // github server used for links, defaults to GITHUB_SERVER_URL
+	Changelog  string // when set, the notes are prepended to this file (relative to the directory)
+}
+
+// newRunOptions returns the default options, using the github environment
+// values for links
+func newRunOptions() *Options {
+	var server = os.Getenv("GITHUB_SERVER_URL")
+	if server == "" {
+		server = defaultServerURL
+	}
+	return &Options{
+		Directory:  "",
+		From:       "",
+		To:         "",
+		Version:    "",
+		Repository: os.Getenv("GITHUB_REPOSITORY"),
+		ServerURL:  server,
+		Changelog:  "",
+	}
+}
+
+// Result contains the generated release notes
+type Result struct {
+	Version          string         `json:"version"`           // heading used for the notes
+	From             string         `json:"from"`              // reference the notes start from, empty for the full history
+	To               string         `json:"to"`                // reference the notes finish at
+	Notes            string         `json:"notes"`             // markdown release notes
+	Entries          []*notes.Entry `json:"entries"`           // commits included in the notes
+	Changelog        string         `json:"changelog"`         // path of the changelog, when used
+	ChangelogUpdated bool           `json:"changelog_updated"` // if the notes were added to the changelog
+}
+
+// Outputs returns the string values used for github outputs
+func (self *Result) Outputs() (outputs map[string]string) {
+	outputs = map[string]string{}
+	if self == nil {
+		return
+	}
+	outputs = map[string]string{
+		"version":           self.Version,
+		"from":              self.From,
+		"to":                self.To,
+		"notes":             self.Notes,
+		"changelog":         self.Changelog,
+		"changelog_updated": strconv.FormatBool(self.ChangelogUpdated),
+	}
+	return
+}
+
+// resolve returns the commit for the reference, using HEAD when empty
+func resolve(lg *slog.Logger, repository *git.Repository, reference string) (commit *object.Commit, err error) {
+	var ref *plumbing.Reference
+
+	if reference == "" {
+		ref, err = repository.Head()
+	} else {
+		ref, err = commits.FindReference(lg, repository, reference)
+	}
+	if err != nil {
+		return
+	}
+	commit, err = repository.CommitObject(ref.Hash())
+	return
+}
+
+// history returns all commits reachable from the commit, used when there
+// is no previous release to compare against
+func history(repository *git.Repository, commit *object.Commit) (all []*object.Commit, err error) {
+	var iter object.CommitIter
+
+	all = []*object.Commit{}
+	if iter, err = repository.Log(&git.LogOptions{From: commit.Hash}); err != nil {
+		return
+	}
+	err = iter.ForEach(func(c *object.Commit) error {
+		all = append(all, c)
+		return nil
+	})
+	return
+}
+
+// previous finds the last release tag before the version
+func previous(lg *slog.Logger, repository *git.Repository, version string) (from string, err error) {
+	var (
+		gittags []*plumbing.Reference
+		semvers []*semver.Semver
+	)
+	if gittags, err = tags.All(lg, repository); err != nil {
+		return
+	}
+	if semvers, err = semver.FromGitRefs(gittags); err != nil {
+		return
+	}
+	if last := notes.PreviousRelease(lg, semvers, version); last != nil {
+		from = last.Original
+	}
+	return
+}
+
+// Run generates the release notes for the commits between From and To.
+//
+// When From is empty the last release before To (or before Version when
+// To is not a semver tag) is used, and when there are no releases the full
+// history is included.
+func Run(lg *slog.Logger, options *Options) (result *Result, err error) {
+	var (
+		repository    *git.Repository
+		head          *object.Commit
+		base          *object.Commit
+		newCommits    []*object.Commit
+		repositoryURL string
+	)
+	lg = lg.With("operation", "releasenotes.Run", "from", options.From, "to", options.To)
+
+	if repository, err = repo.FromDir(options.Directory); err != nil {
+		return
+	}
+	if head, err = resolve(lg, repository, options.To); err != nil {
+		return
+	}
+
+	result = &Result{Version: options.Version, From: options.From, To: options.To}
+	if result.To == "" {
+		result.To = "HEAD"
+	}
+	if result.Version == "" {
+		result.Version = result.To
+	}
+	if result.From == "" {
+		if result.From, err = previous(lg, repository, result.Version); err != nil {
+			return
+		}
+	}
+
+	lg.Info("finding commits ... ", "from", result.From, "to", result.To)
+	if result.From == "" {
+		newCommits, err = history(repository, head)
+	} else if base, err = resolve(lg, repository, result.From); err == nil {
+		newCommits, err = commits.DiffBetween(lg, repository, base.Hash, head.Hash)
+	}
+	if err != nil {
+		return
+	}
+
+	if options.Repository != "" {
+		repositoryURL = strings.TrimSuffix(options.ServerURL, "/") + "/" + options.Repository
+	}
+	rn := &notes.Notes{
+		Version:       result.Version,
+		Date:          head.Committer.When,
+		RepositoryURL: repositoryURL,
+		Entries:       notes.FromCommits(lg, newCommits),
+	}
+	result.Entries = rn.Entries
+	result.Notes = rn.Markdown()
+
+	if options.Changelog != "" {
+		result.Changelog = options.Changelog
+		if !filepath.IsAbs(result.Changelog) {
+			result.Changelog = filepath.Join(options.Directory, result.Changelog)
+		}
+		result.ChangelogUpdated, err = notes.Prepend(lg, result.Changelog, result.Version, result.Notes)
+	}
+	return
+}
+
+// Command returns the release-notes command, with the flags registered
+// against its own options. The repository directory comes from the global
+// flags.
+func Command() *commands.Command {
+	return &commands.Command{
+		Name:        "release-notes",
+		Description: "Generate markdown release notes from the commits between tags.",
+		Flags: func(fs *flag.FlagSet) func(lg *slog.Logger, globals *commands.Globals) (commands.Result, error) {
+			var options = newRunOptions()
+
+			fs.StringVar(&options.From, "from", options.From, "Tag or reference to start from. Defaults to the last release before --to.")
+			fs.StringVar(&options.To, "to", options.To, "Tag or reference to finish at. Defaults to HEAD.")
+			fs.StringVar(&options.Version, "version", options.Version, "Heading for the notes, like the tag being released. Defaults to --to.")
+			fs.StringVar(&options.Repository, "repository", options.Repository, "Repository (owner/name) used to link pull requests and commits. Defaults to GITHUB_REPOSITORY.")
+			fs.StringVar(&options.ServerURL, "server-url", options.ServerURL, "GitHub server used for links. Defaults to GITHUB_SERVER_URL.")
+			fs.StringVar(&options.Changelog, "changelog", options.Changelog, "When set, prepend the notes to this file (like CHANGELOG.md), relative to the directory.")
+
+			return func(lg *slog.Logger, globals *commands.Globals) (commands.Result, error) {
+				options.Directory = globals.Directory
+				return Run(lg, options)
+			}
+		},
+	}
+}
@@ -0,0 +1,151 @@
+package releasenotes
+
+import (
+	"opg-github-actions/action/internal/logger"
+	"opg-github-actions/action/internal/notes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// testRepository creates a repo with a v1.0.0 tag on the first commit and
+// a v1.1.0 tag on the last commit
+func testRepository(t *testing.T, dir string) {
+	t.Helper()
+	var (
+		r, _     = git.PlainInit(dir, false)
+		w, _     = r.Worktree()
+		when     = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+		messages = []string{
+			"initial commit",
+			"feat(cli): add release notes (#4)",
+			"correct typo #patch",
+			"update readme",
+		}
+	)
+	for i, msg := range messages {
+		sig := &object.Signature{Name: "go test", Email: "1+gotest@users.noreply.github.com", When: when.Add(time.Duration(i) * time.Hour)}
+		hash, err := w.Commit(msg, &git.CommitOptions{AllowEmptyCommits: true, Author: sig, Committer: sig})
+		if err != nil {
+			t.Fatalf("failed to create commit: %s", err.Error())
+		}
+		if i == 0 {
+			r.CreateTag("v1.0.0", hash, nil)
+		}
+		if i == len(messages)-1 {
+			r.CreateTag("v1.1.0", hash, nil)
+		}
+	}
+}
+
+type rnFixture struct {
+	Options  *Options
+	From     string
+	Entries  map[notes.Group]int
+	Contains []string
+}
+
+func TestReleaseNotesRun(t *testing.T) {
+	var (
+		lg  = logger.New("error", "text")
+		dir = t.TempDir()
+	)
+	testRepository(t, dir)
+
+	var tests = []*rnFixture{
+		// previous release is found from the tag
+		{
+			Options:  &Options{To: "v1.1.0"},
+			From:     "v1.0.0",
+			Entries:  map[notes.Group]int{notes.GROUP_FEATURES: 1, notes.GROUP_FIXES: 1, notes.GROUP_OTHER: 1},
+			Contains: []string{"## v1.1.0 (2026-10-01)", "- **cli:** add release notes ([#4](https://github.com/owner/repo/pull/4)) by @gotest", "- correct typo ("},
+		},
+		// no earlier release, so the full history is used
+		{
+			Options: &Options{To: "v1.0.0"},
+			From:    "",
+			Entries: map[notes.Group]int{notes.GROUP_OTHER: 1},
+		},
+		// HEAD with a version for the heading
+		{
+			Options:  &Options{Version: "v2.0.0"},
+			From:     "v1.1.0",
+			Entries:  map[notes.Group]int{},
+			Contains: []string{"## v2.0.0", "No changes."},
+		},
+		// explicit from
+		{
+			Options: &Options{From: "v1.0.0", Version: "next"},
+			From:    "v1.0.0",
+			Entries: map[notes.Group]int{notes.GROUP_FEATURES: 1, notes.GROUP_FIXES: 1, notes.GROUP_OTHER: 1},
+		},
+	}
+
+	for i, test := range tests {
+		var options = newRunOptions()
+		options.Directory = dir
+		options.Repository = "owner/repo"
+		options.ServerURL = "https://github.com"
+		options.From = test.Options.From
+		options.To = test.Options.To
+		options.Version = test.Options.Version
+
+		res, err := Run(lg, options)
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+			continue
+		}
+		actual := res.Outputs()
+		if actual["from"] != test.From {
+			t.Errorf("[%d] expected from [%s], actual [%s]", i, test.From, actual["from"])
+		}
+		for _, group := range notes.Groups {
+			var count = 0
+			for _, e := range res.Entries {
+				if e.Group == group {
+					count++
+				}
+			}
+			if count != test.Entries[group] {
+				t.Errorf("[%d] expected [%d] %s entries, actual [%d]", i, test.Entries[group], group, count)
+			}
+		}
+		for _, c := range test.Contains {
+			if !strings.Contains(actual["notes"], c) {
+				t.Errorf("[%d] expected notes to contain [%s], actual:\n%s", i, c, actual["notes"])
+			}
+		}
+	}
+}
+
+func TestReleaseNotesChangelog(t *testing.T) {
+	var (
+		lg      = logger.New("error", "text")
+		dir     = t.TempDir()
+		options = newRunOptions()
+	)
+	testRepository(t, dir)
+	options.Directory = dir
+	options.To = "v1.1.0"
+	options.Changelog = "CHANGELOG.md"
+
+	for i, expected := range []bool{true, false} {
+		res, err := Run(lg, options)
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+			continue
+		}
+		if res.ChangelogUpdated != expected {
+			t.Errorf("[%d] expected changelog updated [%t], actual [%t]", i, expected, res.ChangelogUpdated)
+		}
+	}
+	content, _ := os.ReadFile(filepath.Join(dir, "CHANGELOG.md"))
+	if strings.Count(string(content), "## v1.1.0") != 1 {
+		t.Errorf("expected changelog to contain the version once, actual:\n%s", string(content))
+	}
+}
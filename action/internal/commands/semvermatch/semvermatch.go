@@ -0,0 +1,150 @@
+package semvermatch
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"opg-github-actions/action/internal/commands"
+	"opg-github-actions/action/internal/repo"
+	"opg-github-actions/action/internal/semver"
+	"opg-github-actions/action/internal/tags"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+var ErrMissingConstraint = errors.New("error: a version constraint is required")
+
+const ErrNoMatch string = "error: no existing tag matches [%s]"
+
+type Options struct {
+	Directory  string // directory of the git repo, used to find existing tags
+	Constraint string // version range to match, like `^1.2` or `>= 1.3 < 2`
+	Prefix     string // only match tags that start with this prefix, empty uses the default single letter prefix
+	AllowNone  bool   // when true, finding no match is not an error
+}
+
+// newRunOptions returns the default options
+func newRunOptions() *Options {
+	return &Options{
+		Directory:  "",
+		Constraint: "",
+		Prefix:     "",
+		AllowNone:  false,
+	}
+}
+
+// Result contains the highest tag matching the constraint
+type Result struct {
+	Tag     string   `json:"tag"`     // highest matching tag, like v1.4.2
+	Version string   `json:"version"` // highest matching version without the prefix, like 1.4.2
+	Hash    string   `json:"hash"`    // commit the highest matching tag points to
+	Matches []string `json:"matches"` // all matching tags, highest first
+	Found   bool     `json:"found"`   // if any tag matched
+}
+
+// Outputs returns the string values used for github outputs
+func (self *Result) Outputs() (outputs map[string]string) {
+	outputs = map[string]string{}
+	if self == nil {
+		return
+	}
+	outputs = map[string]string{
+		"tag":     self.Tag,
+		"version": self.Version,
+		"hash":    self.Hash,
+		"matches": strings.Join(self.Matches, ","),
+		"found":   strconv.FormatBool(self.Found),
+	}
+	return
+}
+
+// commitHash returns the commit the tag points to, following annotated tags
+func commitHash(repository *git.Repository, ref *plumbing.Reference) string {
+	if tag, err := repository.TagObject(ref.Hash()); err == nil {
+		return tag.Target.String()
+	}
+	return ref.Hash().String()
+}
+
+// Run finds the existing tags within the constraint (see semver.ParseRange)
+// and returns the highest of them, for picking a version to promote.
+func Run(lg *slog.Logger, options *Options) (result *Result, err error) {
+	var (
+		repository *git.Repository
+		gittags    []*plumbing.Reference
+		existing   []*semver.Semver
+		constraint semver.Range
+	)
+	lg = lg.With("operation", "semvermatch.Run", "constraint", options.Constraint)
+
+	if strings.TrimSpace(options.Constraint) == "" {
+		err = ErrMissingConstraint
+		return
+	}
+	if !semver.ValidPrefix(options.Prefix) {
+		err = fmt.Errorf(semver.ErrInvalidPrefix, options.Prefix)
+		return
+	}
+	if constraint, err = semver.ParseRange(options.Constraint); err != nil {
+		return
+	}
+	if repository, err = repo.FromDir(options.Directory); err != nil {
+		return
+	}
+	if gittags, err = tags.All(lg, repository); err != nil {
+		return
+	}
+	if existing, err = semver.FromGitRefsWithScheme(&semver.Semantic{Prefix: options.Prefix}, gittags); err != nil {
+		return
+	}
+
+	matching := constraint.Matching(existing)
+	slices.Reverse(matching)
+	lg.Debug("found matching tags ... ", "count", len(matching))
+
+	result = &Result{Matches: []string{}}
+	for _, s := range matching {
+		result.Matches = append(result.Matches, s.String())
+	}
+	if len(matching) == 0 {
+		if !options.AllowNone {
+			err = fmt.Errorf(ErrNoMatch, constraint.String())
+		}
+		return
+	}
+	highest := matching[0]
+	result.Found = true
+	result.Tag = highest.String()
+	result.Version = highest.Stringy(false)
+	if highest.GitRef != nil {
+		result.Hash = commitHash(repository, highest.GitRef)
+	}
+	return
+}
+
+// Command returns the semver-match command, with the flags registered
+// against its own options. The repository directory comes from the global
+// flags.
+func Command() *commands.Command {
+	return &commands.Command{
+		Name:        "semver-match",
+		Description: "Find the highest existing semver tag matching a constraint.",
+		Flags: func(fs *flag.FlagSet) func(lg *slog.Logger, globals *commands.Globals) (commands.Result, error) {
+			var options = newRunOptions()
+
+			fs.StringVar(&options.Constraint, "constraint", options.Constraint, "Version constraint to match, like `^1.2`, `~1.2.3`, `~> 1.2`, `>= 1.3 < 2`, `1.2 - 1.4` or `^1 || ^2`.")
+			fs.StringVar(&options.Prefix, "prefix", options.Prefix, "Only match tags that start with this prefix (like `chart/`). When empty, tags with an optional single letter prefix are used.")
+			fs.BoolVar(&options.AllowNone, "allow-none", options.AllowNone, "Do not fail when no tag matches; the found output is false instead.")
+
+			return func(lg *slog.Logger, globals *commands.Globals) (commands.Result, error) {
+				options.Directory = globals.Directory
+				return Run(lg, options)
+			}
+		},
+	}
+}
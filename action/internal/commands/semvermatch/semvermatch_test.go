@@ -0,0 +1,87 @@
+package semvermatch
+
+import (
+	"opg-github-actions/action/internal/logger"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+type matchFixture struct {
+	Constraint string
+	Prefix     string
+	AllowNone  bool
+	Expected   map[string]string
+	Error      string
+}
+
+func TestSemverMatchRun(t *testing.T) {
+	var (
+		lg     = logger.New("error", "text")
+		dir    = t.TempDir()
+		author = &object.Signature{Name: "go test", Email: "test@example.com"}
+	)
+	r, _ := git.PlainInit(dir, false)
+	w, _ := r.Worktree()
+	first, _ := w.Commit("initial commit", &git.CommitOptions{AllowEmptyCommits: true, Author: author})
+	second, _ := w.Commit("second commit", &git.CommitOptions{AllowEmptyCommits: true, Author: author})
+	r.CreateTag("v1.2.0", first, nil)
+	r.CreateTag("v1.3.1", first, nil)
+	r.CreateTag("v1.4.0-beta.1", second, nil)
+	r.CreateTag("v2.0.0", first, nil)
+	r.CreateTag("chart/1.4.0", second, nil)
+	// annotated tags return the commit, not the tag object
+	r.CreateTag("v1.3.2", second, &git.CreateTagOptions{Message: "v1.3.2", Tagger: author})
+
+	var tests = []*matchFixture{
+		{
+			Constraint: "^1.2",
+			Expected:   map[string]string{"tag": "v1.3.2", "version": "1.3.2", "hash": second.String(), "matches": "v1.3.2,v1.3.1,v1.2.0", "found": "true"},
+		},
+		{
+			Constraint: ">= 1.3, < 1.3.2 || 2.0.0",
+			Expected:   map[string]string{"tag": "v2.0.0", "version": "2.0.0", "hash": first.String(), "matches": "v2.0.0,v1.3.1", "found": "true"},
+		},
+		{
+			Constraint: "~1.4",
+			Prefix:     "chart/",
+			Expected:   map[string]string{"tag": "chart/1.4.0", "version": "1.4.0", "hash": second.String(), "matches": "chart/1.4.0", "found": "true"},
+		},
+		{
+			Constraint: "^3",
+			AllowNone:  true,
+			Expected:   map[string]string{"tag": "", "version": "", "hash": "", "matches": "", "found": "false"},
+		},
+		{Constraint: "^3", Error: "no existing tag matches"},
+		{Constraint: "", Error: "constraint is required"},
+		{Constraint: "latest", Error: "invalid version constraint"},
+	}
+
+	for i, test := range tests {
+		var options = newRunOptions()
+		options.Directory = dir
+		options.Constraint = test.Constraint
+		options.Prefix = test.Prefix
+		options.AllowNone = test.AllowNone
+
+		res, err := Run(lg, options)
+		if test.Error != "" {
+			if err == nil || !strings.Contains(err.Error(), test.Error) {
+				t.Errorf("[%d] expected error containing [%s], actual [%v]", i, test.Error, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+			continue
+		}
+		actual := res.Outputs()
+		for key, expected := range test.Expected {
+			if actual[key] != expected {
+				t.Errorf("[%d] expected [%s] to be [%s], actual [%s]", i, key, expected, actual[key])
+			}
+		}
+	}
+}
@@ -0,0 +1,660 @@
+package semvertag
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"opg-github-actions/action/internal/branch"
+	"opg-github-actions/action/internal/commands"
+	"opg-github-actions/action/internal/commits"
+	"opg-github-actions/action/internal/repo"
+	"opg-github-actions/action/internal/semver"
+	"opg-github-actions/action/internal/strs"
+	"opg-github-actions/action/internal/tags"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/google/go-github/v74/github"
+)
+
+const ErrNoBranchName string = "branch-name is required, but not found."
+
+// github environment values used for build metadata
+const (
+	EnvRunNumber  string = "GITHUB_RUN_NUMBER"
+	EnvRunAttempt string = "GITHUB_RUN_ATTEMPT"
+)
+
+type Options struct {
+	RepositoryDirectory    string // Directory where the dit repo is
+	Prerelease             bool   // if this is a prerelease of a full release
+	PrereleaseSuffixLength int    // length of the prerelease suffix
+	PrereleaseSuffixHash   bool   // when truncating the prerelease suffix, replace the end with a hash of the full value
+	DefaultBranch          string // default branch name - generally main, used to compare commits against
+	BranchName             string // branch name is used as the prerelease suffix, when empty its found from the environment
+	BranchReference        string // git reference for the branch, defaults to BranchName
+	DefaultBump            string // what to increment the semver by (major, minor, patch)
+	EventContentFile       string // content from pull request title / body where their might be extra #major content
+	WithoutPrefix          bool
+	Prefix                 string // custom tag prefix (`release-`, `chart/`); only tags with this prefix are used, empty uses `v`
+	TestMode               bool
+	Token                  string           // github token used to push tags, when empty GH_TOKEN is used
+	ReleaseLinePattern     string           // regex for maintenance branches (`release/1.x`, see semver.DefaultLinePattern) that restricts versions to that line, empty disables
+	ReleaseLinePatchOnly   bool             // when on a major release line (`1.x`), only allow patch bumps
+	Scheme                 string           // versioning scheme to use (semver or calver)
+	CalVerFormat           string           // format for calendar versions, like `YYYY.0M.MICRO`
+	Now                    func() time.Time // clock used for calendar versions and build metadata dates, defaults to time.Now
+	BuildMetadata          string           // template for build metadata added to the version (`{sha}.{run}`), empty for none
+}
+
+// Result contains the semver tag generated and if it was created
+type Result struct {
+	Tag     string             `json:"tag"`                  // full version including any build metadata (`v1.2.3+abc1234`)
+	Plain   string             `json:"tag_without_metadata"` // the git tag name, without build metadata (`v1.2.3`)
+	Semver  *semver.Components `json:"semver"`               // the tag broken into its parts
+	Hash    string             `json:"hash"`                 // git commit the tag points at
+	Branch  string             `json:"branch"`               // safe branch name used for prerelease suffixes
+	Test    bool               `json:"test"`                 // if test mode was enabled
+	Created bool               `json:"created"`              // if the tag was created and pushed
+	Bump    string             `json:"bump"`                 // increment used (major, minor, patch, none)
+	Latest  bool               `json:"is_latest"`            // if the tag is the highest release, so should be marked as latest
+	Line    string             `json:"release_line"`         // release line (`1.x`) the tag was restricted to, empty when not on a release line
+}
+
+// Outputs returns the string values used for github outputs
+func (self *Result) Outputs() (outputs map[string]string) {
+	outputs = map[string]string{}
+	if self == nil {
+		return
+	}
+	outputs = map[string]string{
+		"tag":                  self.Tag,
+		"tag_without_metadata": self.Plain,
+		"hash":                 self.Hash,
+		"branch":               self.Branch,
+		"test":                 fmt.Sprintf("%t", self.Test),
+		"created":              fmt.Sprintf("%t", self.Created),
+		"bump":                 self.Bump,
+		"is_latest":            fmt.Sprintf("%t", self.Latest),
+		"release_line":         self.Line,
+	}
+	return
+}
+
+// SafeSuffix returns the cleaned and truncated branch name to use as the
+// prerelease suffix
+func (self *Options) SafeSuffix() (safeAndShort string) {
+	if self.PrereleaseSuffixHash {
+		safeAndShort, _ = strs.SafeWithHash(self.BranchName, self.PrereleaseSuffixLength)
+	} else {
+		safeAndShort, _ = strs.Safe(self.BranchName, self.PrereleaseSuffixLength)
+	}
+	return
+}
+
+// newRunOptions helper to return default options merged with
+// overwrites
+func newRunOptions(in *Options) (opts *Options) {
+	opts = &Options{
+		RepositoryDirectory:    "",
+		Prerelease:             false,
+		PrereleaseSuffixLength: 14,
+		PrereleaseSuffixHash:   false,
+		BranchName:             "",
+		BranchReference:        "",
+		DefaultBranch:          "",
+		DefaultBump:            string(semver.PATCH),
+		EventContentFile:       "",
+		WithoutPrefix:          false,
+		Prefix:                 "",
+		TestMode:               true,
+		Token:                  "",
+		ReleaseLinePattern:     "",
+		ReleaseLinePatchOnly:   false,
+		Scheme:                 string(semver.SCHEME_SEMVER),
+		CalVerFormat:           semver.DefaultCalVerFormat,
+		Now:                    time.Now,
+		BuildMetadata:          "",
+	}
+	if in != nil {
+		if in.RepositoryDirectory != "" {
+			opts.RepositoryDirectory = in.RepositoryDirectory
+		}
+
+		if in.PrereleaseSuffixLength > 0 {
+			opts.PrereleaseSuffixLength = in.PrereleaseSuffixLength
+		}
+		if in.BranchName != "" {
+			opts.BranchName = in.BranchName
+		}
+		if in.BranchReference != "" {
+			opts.BranchReference = in.BranchReference
+		}
+		if in.DefaultBranch != "" {
+			opts.DefaultBranch = in.DefaultBranch
+		}
+		if in.DefaultBump != "" {
+			opts.DefaultBump = in.DefaultBump
+		}
+		if in.EventContentFile != "" {
+			opts.EventContentFile = in.EventContentFile
+		}
+		if in.ReleaseLinePattern != "" {
+			opts.ReleaseLinePattern = in.ReleaseLinePattern
+		}
+		if in.Prefix != "" {
+			opts.Prefix = in.Prefix
+		}
+		if in.Scheme != "" {
+			opts.Scheme = in.Scheme
+		}
+		if in.CalVerFormat != "" {
+			opts.CalVerFormat = in.CalVerFormat
+		}
+		if in.Now != nil {
+			opts.Now = in.Now
+		}
+		if in.BuildMetadata != "" {
+			opts.BuildMetadata = in.BuildMetadata
+		}
+		opts.Prerelease = in.Prerelease
+		opts.PrereleaseSuffixHash = in.PrereleaseSuffixHash
+		opts.TestMode = in.TestMode
+		opts.WithoutPrefix = in.WithoutPrefix
+		opts.Token = in.Token
+		opts.ReleaseLinePatchOnly = in.ReleaseLinePatchOnly
+	}
+
+	return
+}
+
+// getExistingSemvers fetches all git tags and converts entries that are valid
+// for the scheme into semvers which are then used to work out the next value
+func getExistingSemvers(lg *slog.Logger, repository *git.Repository, scheme semver.Scheme) (semvers []*semver.Semver, err error) {
+
+	var gittags []*plumbing.Reference // all tags in the repo
+	// get the tags
+	if gittags, err = tags.All(lg, repository); err != nil {
+		lg.Error("error getting tags from repository", "err", err.Error())
+		return
+	}
+	// get the semvers from the tags
+	if semvers, err = semver.FromGitRefsWithScheme(scheme, gittags); err != nil {
+		lg.Error("error getting semvers from tags", "err", err.Error())
+		return
+	}
+	return
+}
+
+// buildMetadata renders the build metadata template for the commit, replacing:
+//
+//   - `{sha}` with the short commit hash
+//   - `{run}` and `{attempt}` with the github workflow run number and attempt
+//   - `{date}` (20261018) and `{datetime}` (20261018093000) with the current UTC time
+//
+// The result is made safe for build metadata (see semver.SafeBuildMetadata)
+func buildMetadata(template string, hash plumbing.Hash, now time.Time) (metadata string) {
+	var replacer = strings.NewReplacer(
+		"{sha}", hash.String()[:7],
+		"{run}", os.Getenv(EnvRunNumber),
+		"{attempt}", os.Getenv(EnvRunAttempt),
+		"{date}", now.UTC().Format("20060102"),
+		"{datetime}", now.UTC().Format("20060102150405"),
+	)
+	metadata = semver.SafeBuildMetadata(replacer.Replace(template))
+	return
+}
+
+// getSemverToUse looks at the semvers and the options passed along and determines if we should be used prerelease or release
+// semver tag and handles prefix usage.
+func getSemverToUse(lg *slog.Logger, scheme semver.Scheme, semvers []*semver.Semver, bump semver.Increment, options *Options) (use *semver.Semver) {
+	use = &semver.Semver{}
+	// decide if we do prerelease or not based on input
+	if options.Prerelease {
+		use = scheme.Prerelease(lg, semvers, bump, options.SafeSuffix())
+	} else {
+		use = scheme.Release(lg, semvers, bump)
+	}
+
+	// setup the prefix, a custom prefix always takes priority
+	if options.Prefix != "" {
+		use.Prefix = options.Prefix
+	} else if options.WithoutPrefix {
+		use.Prefix = ""
+	} else {
+		use.Prefix = "v"
+	}
+
+	return
+}
+
+// createAndPushTag handles the logic of creating and then pushing tags.
+//
+// If we are in test mode, or there is no semver increment to do, then
+// we immediately return and createdTag will be nil
+//
+// If there is no remote on the repository (ie a locally created repo)
+// then the tag is created, but not pushed
+func createAndPushTag(
+	lg *slog.Logger,
+	repository *git.Repository,
+	use *semver.Semver,
+	bump semver.Increment,
+	auth *http.BasicAuth,
+	existing []*semver.Semver,
+	options *Options) (createdTag *plumbing.Reference, err error) {
+
+	var (
+		remotes              []*git.Remote
+		tagName              string = use.StringyWithoutMetadata(true)
+		errFailedToCreateTag string = "error: failed to create tag [%s]"
+		errFailedToPush      string = "error: failed to push tags to remote [tag: %s]"
+		errTagExists         string = "error: tag [%s] reference already exists as [%s]"
+	)
+	lg = lg.With("operation", "createAndPushTag", "semver", use.String())
+
+	// we do nothing if this is in test mode or we're using no bumping
+	if options.TestMode || bump == semver.NO_BUMP {
+		lg.Debug("returning, test mode / no increment enabled", "test", options.TestMode, "bump", string(bump))
+		return
+	}
+	// build metadata is ignored, so v1.2.3 exists when v1.2.3+build.1 does
+	if found := semver.Find(existing, use); found != nil {
+		err = fmt.Errorf(errTagExists, tagName, found.Original)
+		lg.Error("tag already exists ... ", "err", err.Error())
+		return
+	}
+	// fetch the remotes of the repo
+	remotes, err = repository.Remotes()
+	if err != nil {
+		lg.Error("error getting remotes on repository")
+		return
+	}
+
+	lg.Debug("creating tag ... ")
+	// try to create the tag locally
+	createdTag, err = tags.Create(lg, repository, tagName, use.GitRef.Hash())
+	if err != nil {
+		tagErr := fmt.Errorf(errFailedToCreateTag, tagName)
+		err = errors.Join(tagErr, err)
+		lg.Error("failed to create tag ... ", "err", err.Error())
+		return
+	}
+
+	// if we have some remotes, push
+	if len(remotes) > 0 {
+		lg.Debug("pushing tags ... ")
+		err = tags.Push(lg, repository, auth)
+		if err != nil {
+			tagErr := fmt.Errorf(errFailedToPush, tagName)
+			err = errors.Join(tagErr, err)
+			lg.Error("error pushing tag ... ", "err", err.Error())
+			return
+		}
+	}
+
+	return
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || os.IsNotExist(err) {
+		return false
+	}
+	if info.IsDir() {
+		return false
+	}
+	return true
+}
+
+// getContentFromEventFile reads and parses the event file
+// that might be present at this path
+// Doing it this way as the event content contains special
+// characters that dont escape very well
+func getContentFromEventFile(lg *slog.Logger, file string) (content string) {
+	var (
+		err       error
+		bytes     []byte
+		pr        *github.PullRequest      = nil
+		prEvent   *github.PullRequestEvent = &github.PullRequestEvent{}
+		pushEvent *github.PushEvent        = &github.PushEvent{}
+		raw       map[string]interface{}   = map[string]interface{}{}
+	)
+	lg = lg.With("event_file", file)
+	// ignore empty of missing files
+	if file == "" || !fileExists(file) {
+		lg.Warn("warn: no event file.")
+		return
+	}
+
+	content = ""
+	if bytes, err = os.ReadFile(file); err != nil {
+		lg.Error("error with reading from event file file", "err", err.Error())
+		return
+	}
+
+	// first, unmarshal into a map to test
+	err = json.Unmarshal(bytes, &raw)
+	if err != nil {
+		lg.Error("error unmarshaling event file", "err", err.Error())
+		return
+	}
+	// handle push event
+	if _, ok := raw["commits"]; ok {
+		err = json.Unmarshal(bytes, &pushEvent)
+		if err != nil {
+			lg.Error("error unmarshaling push event", "err", err.Error())
+			return
+		}
+		// merge in the commits
+		for _, c := range pushEvent.Commits {
+			content += *c.Message
+		}
+	}
+	// look if its a pull request, and if so, parse as a
+	// pr and generate the content from that
+	if _, ok := raw["pull_request"]; ok {
+		err = json.Unmarshal(bytes, &prEvent)
+		if err != nil {
+			lg.Error("error unmarshaling pull request event", "err", err.Error())
+			return
+		}
+		// If the pr body or title are empty, then their pointer is nil, so add
+		// handling for that
+		if prEvent != nil && prEvent.PullRequest != nil {
+			pr = prEvent.PullRequest
+		}
+		if pr != nil && pr.Title != nil {
+			content += fmt.Sprintf("%s\n", *pr.Title)
+		}
+		if pr != nil && pr.Body != nil {
+			content += fmt.Sprintf(" %s", *pr.Body)
+		}
+	}
+
+	return
+}
+
+// Run handles gluing together the process of creating a new semver tag from the git repository and outputting the created
+// values.
+//
+//   - Generate a repository object from the directory path arguments (or returns error)
+//   - Finds all existing semver formatted git tags in the repository
+//   - Finds the git sha / hash reference for the configured default branch and the currently checked out location
+//   - Finds all commits that exist in the currently checked out location, but not in default branch tree - these are the new ones
+//     -- Merges the extra-content argument into this data (pull request details)
+//   - Looks at the new commits for #major|minor|patch content to determine the semver increment
+//   - Retry loop
+//     -- Works out the new new tag
+//     -- Creates and pushes the tag
+//   - Outputs data
+//
+// If you have enabled test mode (via `--test`) the tag will not be created or pushed.
+// If there are no new commits, or no commits with #major|minor|patch then the default increment (`--bump`)
+// will be used.
+//
+// Will try to create tag multiple times
+func Run(lg *slog.Logger, options *Options) (result *Result, err error) {
+	var (
+		repository    *git.Repository                                             // the object for this repo
+		semvers       []*semver.Semver                                            // all valid semver tags in the repo
+		candidates    []*semver.Semver                                            // semvers the next version is generated from, restricted to the release line
+		line          *semver.Line                                                // release line for maintenance branches
+		scheme        semver.Scheme                                               // versioning scheme (semver / calver)
+		metadata      string                                                      // build metadata for the version
+		use           *semver.Semver                                              // the semver to use for the prerelease / release
+		lastRelease   *semver.Semver                                              // the last release or default branch
+		baseRef       *plumbing.Reference                                         // git ref for previous ref (main / or last release)
+		currentCommit *plumbing.Reference                                         // git sha / ref for where the git repo currently is
+		createdTag    *plumbing.Reference                                         // the new semver tag thats been created
+		newCommits    []*object.Commit                                            // all commits that exist in the ref
+		auth          *http.BasicAuth                                             // github auth config for pull / pushing to the remote
+		bump          semver.Increment    = semver.Increment(options.DefaultBump) // default increment
+		basePoint     string              = ""                                    // either ref of last release or the default branch
+		errTagExists  string              = "reference already exists"            // in cases where tag exists, look for this error string
+		maxRetries    int                 = 20                                    // max retries
+		linePattern   string              = options.ReleaseLinePattern            // release line pattern, unused for calendar versions
+
+	)
+	auth = &http.BasicAuth{
+		Username: "opg-github-actions",
+		Password: options.Token,
+	}
+	if auth.Password == "" {
+		auth.Password = os.Getenv("GH_TOKEN")
+	}
+
+	// when no branch name is passed, look for it in the environment
+	if options.BranchName == "" {
+		if resolved, e := branch.Resolve(""); e == nil {
+			lg.Info("branch name found from environment ... ", "branch", resolved.Name, "source", string(resolved.Source))
+			options.BranchName = resolved.Name
+			options.BranchReference = resolved.Reference
+		}
+	}
+	if options.BranchReference == "" {
+		options.BranchReference = options.BranchName
+	}
+
+	if options.Prerelease && options.BranchName == "" {
+		err = fmt.Errorf(ErrNoBranchName)
+		return
+	}
+	if scheme, err = semver.NewScheme(semver.SchemeName(options.Scheme), options.Prefix, options.CalVerFormat, options.Now); err != nil {
+		lg.Error("error with versioning scheme", "err", err.Error(), "scheme", options.Scheme)
+		return
+	}
+	// generate a repo
+	if repository, err = repo.FromDir(options.RepositoryDirectory); err != nil {
+		lg.Error("error creating repository from directory", "err", err.Error(), "dir", options.RepositoryDirectory)
+		return
+	}
+
+	// get the semvers from the tags
+	semvers, err = getExistingSemvers(lg, repository, scheme)
+	if err != nil {
+		lg.Error("error getting existing semvers for this repository")
+		return
+	}
+
+	// output all semvers for debuggin
+	lg.Debug("found semvers ... ", "semvers", semvers)
+
+	// on a maintenance branch (`release/1.x`) only use the semvers within that line;
+	// release lines are only used for semver as calendar versions always move forward
+	candidates = semvers
+	if scheme.Name() != semver.SCHEME_SEMVER {
+		linePattern = ""
+	}
+	if line, err = semver.LineFromBranch(linePattern, options.BranchName, !options.ReleaseLinePatchOnly); err != nil {
+		lg.Error("error finding release line for branch", "err", err.Error(), "branch", options.BranchName)
+		return
+	}
+	if line != nil {
+		candidates = line.Filter(semvers)
+		lg.Info("using release line ... ", "line", line.String(), "semvers", len(candidates))
+		if semver.GetLastRelease(lg, candidates) == nil {
+			err = fmt.Errorf(semver.ErrNoReleaseInLine, line.String())
+			return
+		}
+	}
+	// get the last release for the comparison point
+	lastRelease = semver.GetLastRelease(lg, candidates)
+	lg.Debug("last release found ... ", "lastRelease", lastRelease)
+
+	if lastRelease != nil {
+		basePoint = lastRelease.Stringy(true)
+	} else {
+		basePoint = options.DefaultBranch
+	}
+
+	// get the default branch info
+	if baseRef, err = commits.FindReference(lg, repository, basePoint); err != nil {
+		lg.Error("error getting git reference for previous comparison point.", "err", err.Error(), "basePoint", basePoint)
+		return
+	}
+	// get info on the current commit
+	if currentCommit, err = commits.FindReference(lg, repository, options.BranchReference); err != nil {
+		lg.Error("error getting git reference for branch.", "err", err.Error(), "branch", options.BranchName, "reference", options.BranchReference)
+		return
+	}
+	// find new commits between the baseRef (main / last release) and the current commit
+	if newCommits, err = commits.DiffBetween(lg, repository, baseRef.Hash(), currentCommit.Hash()); err != nil {
+		lg.Error("error getting commits between references.", "err", err.Error(), "base", baseRef.Hash().String(), "head", currentCommit.Hash().String())
+		return
+	}
+
+	// add content to the commit list from the event file
+	if extra := getContentFromEventFile(lg, options.EventContentFile); len(extra) > 0 {
+		newCommits = append(newCommits, &object.Commit{Hash: plumbing.ZeroHash, Message: extra})
+	}
+
+	lg.Info("commits found and event file used ... ", "commits", len(newCommits), "event-file", options.EventContentFile)
+
+	// dump the commits for debugging one at a time (messages can be long)
+	for _, c := range newCommits {
+		lg.Debug("commit", "message", c.Message, "hash", c.Hash)
+	}
+
+	// look for bump in the commits,
+	foundBump, _ := semver.GetBumpFromCommits(lg, newCommits, bump)
+	if len(newCommits) > 0 && foundBump != "" {
+		bump = foundBump
+	}
+	// release lines cannot leave their major (or minor) version
+	if line != nil {
+		if err = line.Allows(bump); err != nil {
+			lg.Error("bump not allowed on release line", "err", err.Error(), "bump", bump, "line", line.String())
+			return
+		}
+	}
+
+	// build metadata is added to the version, but not the git tag
+	metadata = buildMetadata(options.BuildMetadata, currentCommit.Hash(), options.Now())
+
+	// retry loop
+	// In some places the semver action may run on the same repository at almost the same time
+	// (such as mono repos with multiple projects)
+	// In those cases we loop multiple times to try to create a new tag for each thing
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		var n = rand.IntN(5)
+		// find the semver and set the git ref to the current place
+		use = getSemverToUse(lg, scheme, candidates, bump, options)
+		use.GitRef = currentCommit
+		use.BuildMetadata = metadata
+		lg.Info("generated semver ... ", "use", use, "attempt", attempt)
+		// create and try to push tags
+		createdTag, err = createAndPushTag(lg, repository, use, bump, auth, candidates, options)
+
+		// if there is an error and its not about existing tags, then exit
+		if err != nil && !strings.Contains(err.Error(), errTagExists) {
+			return
+		}
+		// if there is no error, then break the loop
+		if err == nil {
+			break
+		}
+		lg.Info("need to retry tag creation; sleeping ... ", "seconds", n)
+		time.Sleep(time.Duration(n) * time.Second)
+		// fetch repo to check its up to date
+		repo.Fetch(lg, repository, auth)
+		// refresh the existing semvers so the next attempt includes any new tags
+		if refreshed, e := getExistingSemvers(lg, repository, scheme); e == nil {
+			semvers, candidates = refreshed, refreshed
+			if line != nil {
+				candidates = line.Filter(semvers)
+			}
+		}
+
+	}
+
+	result = &Result{
+		Tag:     use.String(),
+		Plain:   use.StringyWithoutMetadata(true),
+		Semver:  use.Components(),
+		Hash:    use.GitRef.Hash().String(),
+		Branch:  options.SafeSuffix(),
+		Test:    options.TestMode,
+		Created: (createdTag != nil),
+		Bump:    string(bump),
+		Latest:  semver.IsLatest(lg, semvers, use),
+	}
+	if line != nil {
+		result.Line = line.String()
+	}
+
+	return
+}
+
+// Command returns the semver command, with the flags registered against
+// its own options. The repository directory and token come from the global
+// flags.
+func Command() *commands.Command {
+	return &commands.Command{
+		Name:        "semver",
+		Description: "Generate, create and push the next semver tag.",
+		Inputs: map[string]string{
+			"github_token":            "token",
+			"branch_name":             "branch",
+			"prerelease":              "prerelease",
+			"prelease_suffix_length":  "prerelease-suffix-length",
+			"prerelease_suffix_hash":  "prerelease-suffix-hash",
+			"default_bump":            "default-bump",
+			"without_prefix":          "without-prefix",
+			"prefix":                  "prefix",
+			"build_metadata":          "build-metadata",
+			"test":                    "test",
+			"release_line_pattern":    "release-line-pattern",
+			"release_line_patch_only": "release-line-patch-only",
+			"scheme":                  "scheme",
+			"calver_format":           "calver-format",
+		},
+		Flags: func(fs *flag.FlagSet) func(lg *slog.Logger, globals *commands.Globals) (commands.Result, error) {
+			var options = newRunOptions(&Options{DefaultBranch: "main"})
+
+			// branch details
+			fs.StringVar(&options.BranchName, "branch", options.BranchName, "The current branch name to use for prerelease suffixes. When empty, GITHUB_HEAD_REF and then GITHUB_REF are used.")
+			fs.StringVar(&options.DefaultBranch, "default-branch", options.DefaultBranch, "The default branch name for this repo - used for commit comparisons")
+			// prerelease related options
+			fs.BoolVar(&options.Prerelease, "prerelease", options.Prerelease, "Set to true to generate a prerelease version.")
+			fs.IntVar(&options.PrereleaseSuffixLength, "prerelease-suffix-length", options.PrereleaseSuffixLength, "Set the max length to use for tag suffixes")
+			fs.BoolVar(&options.PrereleaseSuffixHash, "prerelease-suffix-hash", options.PrereleaseSuffixHash, "When truncating the tag suffix, replace the end with a short hash of the full branch name.")
+			// Semver increments
+			fs.StringVar(&options.DefaultBump, "default-bump", options.DefaultBump, "The default value to increment semver by if no comment if found. If set to `none`, last tag is returned. (default: patch)")
+			// use a prefix?
+			fs.BoolVar(&options.WithoutPrefix, "without-prefix", options.WithoutPrefix, "Use to disable prefix usage.")
+			fs.StringVar(&options.Prefix, "prefix", options.Prefix, "Custom tag prefix, like release- or chart/. Only existing tags with this prefix are used and new tags are created with it. Takes priority over without-prefix.")
+			// build metadata
+			fs.StringVar(&options.BuildMetadata, "build-metadata", options.BuildMetadata, "Template for build metadata added to the version (not the git tag), like {sha}.{run}. Supports {sha}, {run}, {attempt}, {date} and {datetime}.")
+			// versioning scheme
+			fs.StringVar(&options.Scheme, "scheme", options.Scheme, "Versioning scheme to use, either semver or calver.")
+			fs.StringVar(&options.CalVerFormat, "calver-format", options.CalVerFormat, "Format for calendar versions using YYYY, YY, 0Y, MM, 0M, WW, 0W, DD, 0D and MICRO, like YYYY.0M.MICRO. Must end with MICRO.")
+			// maintenance branch release lines
+			fs.StringVar(&options.ReleaseLinePattern, "release-line-pattern", options.ReleaseLinePattern, "Regex with named major (and optional minor) groups matching maintenance branches, like release/1.x. Versions on matching branches stay within that line. Empty (the default) disables release lines.")
+			fs.BoolVar(&options.ReleaseLinePatchOnly, "release-line-patch-only", options.ReleaseLinePatchOnly, "Only allow patch bumps on major release lines (release/1.x). Minor lines (release/1.4.x) are always patch only.")
+			// test mode - disables creating tags
+			fs.BoolVar(&options.TestMode, "test", options.TestMode, "Set to true to disable creating tag.")
+			//
+			fs.StringVar(&options.EventContentFile, "event-content-file", options.EventContentFile, "The github event file that contains extra content")
+
+			return func(lg *slog.Logger, globals *commands.Globals) (commands.Result, error) {
+				options.RepositoryDirectory = globals.Directory
+				options.Token = globals.Token
+				return Run(lg, options)
+			}
+		},
+	}
+}
+
+func debug[T any](item T) {
+	bytes, _ := json.MarshalIndent(item, "", "  ")
+	fmt.Printf("%+v\n", string(bytes))
+}
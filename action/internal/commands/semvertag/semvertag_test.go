@@ -1,11 +1,14 @@
-package main
+package semvertag
 
 import (
 	"fmt"
 	"math/rand"
+	"opg-github-actions/action/internal/branch"
 	"opg-github-actions/action/internal/logger"
 	"opg-github-actions/action/internal/semver"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
@@ -97,7 +100,8 @@ func TestMainPush(t *testing.T) {
 			opts.DefaultBranch = defBranch.Name().Short()
 		}
 		// now run the command and compare
-		res, err := Run(lg, opts)
+		result, err := Run(lg, opts)
+		res := result.Outputs()
 		// check error states
 		if !test.ShouldError && err != nil {
 			t.Errorf("[%d] unexpected error: %s", i, err.Error())
@@ -179,6 +183,31 @@ func TestMainPR(t *testing.T) {
 				},
 			},
 		},
+		// test a prerelease tag for a branch that would clash with a similar
+		// branch, but uses the hash suffix so generates a distinct tag
+		{
+			ExpectedTag:    "v1.0.1-renovate7d0047.1",
+			ExpectedBump:   string(semver.PATCH),
+			ExpectedBranch: "renovate7d0047",
+			ShouldError:    false,
+			CreateRelease:  true,
+			Input: &Options{
+				Prerelease:           true,
+				PrereleaseSuffixHash: true,
+				BranchName:           "renovate-feature-b",
+			},
+			Commits: []*tSemTestCommit{
+				{
+					Message: "different, but similar commit that should trigger patch only",
+					Branch:  "renovate-feature-a",
+					Tag:     "v1.0.1-renovatefeatur.1",
+				},
+				{
+					Message: "single commit thats not even a patch but using defaults",
+					Branch:  "renovate-feature-b",
+				},
+			},
+		},
 		// trying to get the last semver, but there is a commit on a the branch
 		// being merged, so it should be incremented by patch (form commit)
 		{
@@ -476,7 +505,8 @@ func TestMainPR(t *testing.T) {
 			opts.DefaultBranch = defBranch.Name().Short()
 		}
 		// now run the command and compare
-		res, err := Run(lg, opts)
+		result, err := Run(lg, opts)
+		res := result.Outputs()
 		// check error states
 		if !test.ShouldError && err != nil {
 			t.Errorf("[%d] unexpected error: %s", i, err.Error())
@@ -505,6 +535,430 @@ func TestMainPR(t *testing.T) {
 
 }
 
+// Test release line branches (release/1.x) only generate versions within
+// that line, even when there are higher releases on the default branch
+func TestMainReleaseLine(t *testing.T) {
+	var lg = logger.New("error", "text")
+	var base = []*tSemTestCommit{
+		{Message: "release 1.1", Tag: "v1.1.0"},
+		{Message: "release 2", Tag: "v2.0.0"},
+	}
+	var tests = []*tSemTest{
+		// patch on the 1.x line ignores v2.0.0
+		{
+			ExpectedTag:   "v1.1.1",
+			ExpectedBump:  string(semver.PATCH),
+			CreateRelease: true,
+			Input:         &Options{BranchName: "release/1.x", ReleaseLinePattern: semver.DefaultLinePattern},
+			Commits:       append(base, &tSemTestCommit{Message: "fix on the release line", Branch: "release/1.x"}),
+		},
+		// minor bumps are allowed on major lines by default
+		{
+			ExpectedTag:   "v1.2.0",
+			ExpectedBump:  string(semver.MINOR),
+			CreateRelease: true,
+			Input:         &Options{BranchName: "release/1.x", ReleaseLinePattern: semver.DefaultLinePattern},
+			Commits:       append(base, &tSemTestCommit{Message: "feature on the release line #minor", Branch: "release/1.x"}),
+		},
+		// major bumps would leave the line
+		{
+			ShouldError:   true,
+			CreateRelease: true,
+			Input:         &Options{BranchName: "release/1.x", ReleaseLinePattern: semver.DefaultLinePattern},
+			Commits:       append(base, &tSemTestCommit{Message: "breaking #major", Branch: "release/1.x"}),
+		},
+		// minor bumps can be disabled
+		{
+			ShouldError:   true,
+			CreateRelease: true,
+			Input:         &Options{BranchName: "release/1.x", ReleaseLinePattern: semver.DefaultLinePattern, ReleaseLinePatchOnly: true},
+			Commits:       append(base, &tSemTestCommit{Message: "feature #minor", Branch: "release/1.x"}),
+		},
+		// minor lines are always patch only
+		{
+			ExpectedTag:   "v1.0.1",
+			ExpectedBump:  string(semver.PATCH),
+			CreateRelease: true,
+			Input:         &Options{BranchName: "release/1.0.x", ReleaseLinePattern: semver.DefaultLinePattern},
+			Commits:       append(base, &tSemTestCommit{Message: "fix on the minor line", Branch: "release/1.0.x"}),
+		},
+		// a line without any releases
+		{
+			ShouldError:   true,
+			CreateRelease: true,
+			Input:         &Options{BranchName: "release/3.x", ReleaseLinePattern: semver.DefaultLinePattern},
+			Commits:       append(base, &tSemTestCommit{Message: "fix", Branch: "release/3.x"}),
+		},
+	}
+
+	for i, test := range tests {
+		var (
+			dir          = t.TempDir()
+			r, defBranch = randomRepository(dir, test.CreateRelease)
+			w, _         = r.Worktree()
+		)
+		err := testSetup(test, r, w, defBranch)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		opts := newRunOptions(test.Input)
+		opts.RepositoryDirectory = dir
+		opts.DefaultBranch = defBranch.Name().Short()
+
+		result, err := Run(lg, opts)
+		res := result.Outputs()
+		if !test.ShouldError && err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+		} else if test.ShouldError && err == nil {
+			t.Errorf("[%d] expected an error, but did not get one", i)
+		}
+		if res["tag"] != test.ExpectedTag {
+			t.Errorf("[%d] expected tag [%s] actual [%s]", i, test.ExpectedTag, res["tag"])
+		}
+		if res["bump"] != test.ExpectedBump {
+			t.Errorf("[%d] expected bump [%s] actual [%s]", i, test.ExpectedBump, res["bump"])
+		}
+		if !test.ShouldError && (res["release_line"] == "" || res["is_latest"] != "false") {
+			t.Errorf("[%d] expected a release line that is not latest, actual [%s] [%s]", i, res["release_line"], res["is_latest"])
+		}
+	}
+}
+
+// Test release lines are only used when a pattern is set
+func TestMainReleaseLineDisabled(t *testing.T) {
+	var (
+		lg           = logger.New("error", "text")
+		dir          = t.TempDir()
+		r, defBranch = randomRepository(dir, true)
+		w, _         = r.Worktree()
+		test         = &tSemTest{
+			CreateRelease: true,
+			Input:         &Options{BranchName: "release/1.x"},
+			Commits: []*tSemTestCommit{
+				{Message: "release 1.1", Tag: "v1.1.0"},
+				{Message: "release 2", Tag: "v2.0.0"},
+				{Message: "fix on the release branch", Branch: "release/1.x"},
+			},
+		}
+	)
+	if err := testSetup(test, r, w, defBranch); err != nil {
+		t.Fatal(err)
+	}
+	opts := newRunOptions(test.Input)
+	opts.RepositoryDirectory = dir
+	opts.DefaultBranch = defBranch.Name().Short()
+
+	result, err := Run(lg, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	res := result.Outputs()
+	if res["tag"] != "v2.0.1" || res["release_line"] != "" {
+		t.Errorf("expected [v2.0.1] without a release line, actual [%s] [%s]", res["tag"], res["release_line"])
+	}
+}
+
+// Test calendar versions use the same tag discovery and prerelease handling
+// with a fixed clock
+func TestMainCalVer(t *testing.T) {
+	var (
+		lg  = logger.New("error", "text")
+		now = func() time.Time { return time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC) }
+	)
+	var tests = []*tSemTest{
+		// next micro for the month, ignoring the semver tag
+		{
+			ExpectedTag:   "v2026.10.2",
+			ExpectedBump:  string(semver.PATCH),
+			CreateRelease: true,
+			Input:         &Options{Scheme: "calver", Now: now},
+			Commits: []*tSemTestCommit{
+				{Message: "release", Tag: "v2026.10.1"},
+				{Message: "new commit"},
+			},
+		},
+		// new month starts at 0
+		{
+			ExpectedTag:   "2026.10.0",
+			ExpectedBump:  string(semver.MINOR),
+			CreateRelease: true,
+			Input:         &Options{Scheme: "calver", Now: now, WithoutPrefix: true},
+			Commits: []*tSemTestCommit{
+				{Message: "release", Tag: "v2026.09.4"},
+				{Message: "new commit #minor"},
+			},
+		},
+		// prerelease with a daily format
+		{
+			ExpectedTag:   "v2026.10.18-1-testbranch.1",
+			ExpectedBump:  string(semver.PATCH),
+			CreateRelease: true,
+			Input:         &Options{Scheme: "calver", CalVerFormat: "YYYY.0M.0D-MICRO", Now: now, Prerelease: true, BranchName: "test-branch"},
+			Commits: []*tSemTestCommit{
+				{Message: "release", Tag: "v2026.10.18-0"},
+				{Message: "commit on the branch", Branch: "test-branch"},
+			},
+		},
+		// invalid format
+		{
+			ShouldError:   true,
+			CreateRelease: true,
+			Input:         &Options{Scheme: "calver", CalVerFormat: "YYYY.0M", Now: now},
+			Commits:       []*tSemTestCommit{{Message: "new commit"}},
+		},
+	}
+
+	for i, test := range tests {
+		var (
+			dir          = t.TempDir()
+			r, defBranch = randomRepository(dir, test.CreateRelease)
+			w, _         = r.Worktree()
+		)
+		err := testSetup(test, r, w, defBranch)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		opts := newRunOptions(test.Input)
+		opts.RepositoryDirectory = dir
+		opts.DefaultBranch = defBranch.Name().Short()
+		if opts.BranchName == "" {
+			opts.BranchName = opts.DefaultBranch
+		}
+
+		result, err := Run(lg, opts)
+		res := result.Outputs()
+		if !test.ShouldError && err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+		} else if test.ShouldError && err == nil {
+			t.Errorf("[%d] expected an error, but did not get one", i)
+		}
+		if res["tag"] != test.ExpectedTag {
+			t.Errorf("[%d] expected tag [%s] actual [%s]", i, test.ExpectedTag, res["tag"])
+		}
+		if res["bump"] != test.ExpectedBump {
+			t.Errorf("[%d] expected bump [%s] actual [%s]", i, test.ExpectedBump, res["bump"])
+		}
+	}
+}
+
+// Test custom prefixes only use matching tags and create new tags with
+// that prefix
+func TestMainPrefix(t *testing.T) {
+	var lg = logger.New("error", "text")
+	var tests = []*tSemTest{
+		// ignores the v1.0.0 tag and the higher v3.0.0 tag
+		{
+			ExpectedTag:   "release-1.2.1",
+			ExpectedBump:  string(semver.PATCH),
+			CreateRelease: true,
+			Input:         &Options{Prefix: "release-"},
+			Commits: []*tSemTestCommit{
+				{Message: "release", Tag: "release-1.2.0"},
+				{Message: "other", Tag: "v3.0.0"},
+				{Message: "new commit"},
+			},
+		},
+		// no existing tags with the prefix, without-prefix is ignored
+		{
+			ExpectedTag:   "chart/0.0.1",
+			ExpectedBump:  string(semver.PATCH),
+			CreateRelease: true,
+			Input:         &Options{Prefix: "chart/", WithoutPrefix: true},
+			Commits: []*tSemTestCommit{
+				{Message: "new chart"},
+			},
+		},
+		// prereleases
+		{
+			ExpectedTag:   "terraform-module-2.0.1-testbranch.1",
+			ExpectedBump:  string(semver.PATCH),
+			CreateRelease: true,
+			Input:         &Options{Prefix: "terraform-module-", Prerelease: true, BranchName: "test-branch"},
+			Commits: []*tSemTestCommit{
+				{Message: "release", Tag: "terraform-module-2.0.0"},
+				{Message: "commit on the branch", Branch: "test-branch"},
+			},
+		},
+		// invalid prefix
+		{
+			ShouldError:   true,
+			CreateRelease: true,
+			Input:         &Options{Prefix: "release-1"},
+			Commits:       []*tSemTestCommit{{Message: "new commit"}},
+		},
+	}
+
+	for i, test := range tests {
+		var (
+			dir          = t.TempDir()
+			r, defBranch = randomRepository(dir, test.CreateRelease)
+			w, _         = r.Worktree()
+		)
+		err := testSetup(test, r, w, defBranch)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		opts := newRunOptions(test.Input)
+		opts.RepositoryDirectory = dir
+		opts.DefaultBranch = defBranch.Name().Short()
+		if opts.BranchName == "" {
+			opts.BranchName = opts.DefaultBranch
+		}
+
+		result, err := Run(lg, opts)
+		res := result.Outputs()
+		if !test.ShouldError && err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+		} else if test.ShouldError && err == nil {
+			t.Errorf("[%d] expected an error, but did not get one", i)
+		}
+		if res["tag"] != test.ExpectedTag {
+			t.Errorf("[%d] expected tag [%s] actual [%s]", i, test.ExpectedTag, res["tag"])
+		}
+		if res["bump"] != test.ExpectedBump {
+			t.Errorf("[%d] expected bump [%s] actual [%s]", i, test.ExpectedBump, res["bump"])
+		}
+	}
+}
+
+// Test build metadata is added to the version, but not the git tag, and is
+// ignored when finding the last release
+func TestMainBuildMetadata(t *testing.T) {
+	var (
+		lg  = logger.New("error", "text")
+		now = func() time.Time { return time.Date(2026, time.October, 18, 9, 30, 0, 0, time.UTC) }
+	)
+	t.Setenv(EnvRunNumber, "42")
+
+	var tests = []*tSemTest{
+		{
+			ExpectedTag:   "v1.0.1+{sha}.run-42.20261018",
+			ExpectedBump:  string(semver.PATCH),
+			CreateRelease: true,
+			Input:         &Options{BuildMetadata: "{sha}.run-{run}.{date}", Now: now},
+			Commits:       []*tSemTestCommit{{Message: "new commit"}},
+		},
+		// an existing tag with build metadata is still used as the last release
+		{
+			ExpectedTag:   "v1.0.2",
+			ExpectedBump:  string(semver.PATCH),
+			CreateRelease: true,
+			Input:         &Options{},
+			Commits: []*tSemTestCommit{
+				{Message: "release", Tag: "v1.0.1+build.1"},
+				{Message: "new commit"},
+			},
+		},
+	}
+
+	for i, test := range tests {
+		var (
+			dir          = t.TempDir()
+			r, defBranch = randomRepository(dir, test.CreateRelease)
+			w, _         = r.Worktree()
+		)
+		err := testSetup(test, r, w, defBranch)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		opts := newRunOptions(test.Input)
+		opts.RepositoryDirectory = dir
+		opts.DefaultBranch = defBranch.Name().Short()
+		opts.BranchName = opts.DefaultBranch
+
+		result, err := Run(lg, opts)
+		res := result.Outputs()
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+			continue
+		}
+		expected := strings.ReplaceAll(test.ExpectedTag, "{sha}", res["hash"][:7])
+		if res["tag"] != expected {
+			t.Errorf("[%d] expected tag [%s] actual [%s]", i, expected, res["tag"])
+		}
+		plain := strings.Split(expected, "+")[0]
+		if res["tag_without_metadata"] != plain {
+			t.Errorf("[%d] expected tag without metadata [%s] actual [%s]", i, plain, res["tag_without_metadata"])
+		}
+		if _, err = r.Tag(plain); err != nil {
+			t.Errorf("[%d] expected git tag [%s] to be created: %s", i, plain, err.Error())
+		}
+	}
+}
+
+// Test the branch name being found from the github environment values
+// when it is not passed directly
+func TestMainBranchFromEnvironment(t *testing.T) {
+	var lg = logger.New("error", "text")
+	var tests = []*tSemTest{
+		// pull request, so uses the head ref
+		{
+			ExpectedTag:    "v1.0.1-testbranchenv.1",
+			ExpectedBump:   string(semver.PATCH),
+			ExpectedBranch: "testbranchenv",
+			CreateRelease:  true,
+			Input:          &Options{Prerelease: true},
+			Commits: []*tSemTestCommit{
+				{Message: "commit on the branch", Branch: "test-branch-env"},
+			},
+		},
+		// merge queue, so uses the pr number from the ref
+		{
+			ExpectedTag:    "v1.1.0-pr7.1",
+			ExpectedBump:   string(semver.MINOR),
+			ExpectedBranch: "pr7",
+			CreateRelease:  true,
+			Input:          &Options{Prerelease: true},
+			Commits: []*tSemTestCommit{
+				{Message: "merge queue commit #minor", Branch: "gh-readonly-queue/master/pr-7-f2bd01600ded17a4f3f2c8348443b33cd48c8902"},
+			},
+		},
+	}
+	var envs = []map[string]string{
+		{branch.EnvHeadRef: "test-branch-env", branch.EnvRef: "refs/pull/1/merge"},
+		{branch.EnvHeadRef: "", branch.EnvRef: "refs/heads/gh-readonly-queue/master/pr-7-f2bd01600ded17a4f3f2c8348443b33cd48c8902"},
+	}
+
+	for i, test := range tests {
+		var (
+			dir          = t.TempDir()
+			r, defBranch = randomRepository(dir, test.CreateRelease)
+			w, _         = r.Worktree()
+		)
+		for k, v := range envs[i] {
+			t.Setenv(k, v)
+		}
+
+		err := testSetup(test, r, w, defBranch)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		opts := newRunOptions(test.Input)
+		opts.RepositoryDirectory = dir
+		opts.DefaultBranch = defBranch.Name().Short()
+
+		result, err := Run(lg, opts)
+		res := result.Outputs()
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+		}
+		if res["tag"] != test.ExpectedTag {
+			t.Errorf("[%d] expected tag [%s] actual [%s]", i, test.ExpectedTag, res["tag"])
+		}
+		if res["bump"] != test.ExpectedBump {
+			t.Errorf("[%d] expected bump [%s] actual [%s]", i, test.ExpectedBump, res["bump"])
+		}
+		if res["branch"] != test.ExpectedBranch {
+			t.Errorf("[%d] expected branch [%s] actual [%s]", i, test.ExpectedBranch, res["branch"])
+		}
+	}
+}
+
 // testSetup generates some base commits / branches / tags to use in test scenarios
 func testSetup(test *tSemTest, r *git.Repository, w *git.Worktree, defBranch *plumbing.Reference) (err error) {
 	var author = &object.Signature{Name: "go test", Email: "test@example.com"}
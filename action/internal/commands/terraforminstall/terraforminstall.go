@@ -0,0 +1,89 @@
+package terraforminstall
+
+import (
+	"errors"
+	"flag"
+	"log/slog"
+	"opg-github-actions/action/internal/commands"
+	"opg-github-actions/action/internal/terraform"
+	"path/filepath"
+	"strconv"
+)
+
+var ErrMissingVersion = errors.New("error: a terraform version is required")
+
+// newRunOptions returns the default options
+func newRunOptions() *terraform.InstallOptions {
+	return &terraform.InstallOptions{
+		Version:        "",
+		Mirror:         terraform.DefaultMirror,
+		CacheDirectory: terraform.DefaultCacheDirectory(),
+		OS:             "",
+		Arch:           "",
+	}
+}
+
+// Result contains where terraform was installed
+type Result struct {
+	Path      string `json:"path"`      // full path to the terraform binary
+	Directory string `json:"directory"` // directory containing the binary, to add to PATH
+	Version   string `json:"version"`   // version installed
+	Cached    bool   `json:"cached"`    // if the version was already in the cache
+}
+
+// Outputs returns the string values used for github outputs
+func (self *Result) Outputs() (outputs map[string]string) {
+	outputs = map[string]string{}
+	if self == nil {
+		return
+	}
+	outputs = map[string]string{
+		"path":      self.Path,
+		"directory": self.Directory,
+		"version":   self.Version,
+		"cached":    strconv.FormatBool(self.Cached),
+	}
+	return
+}
+
+// Run installs the terraform version and returns where it was installed to
+func Run(lg *slog.Logger, options *terraform.InstallOptions) (result *Result, err error) {
+	var installed *terraform.Installed
+
+	if options.Version == "" {
+		err = ErrMissingVersion
+		return
+	}
+	if installed, err = terraform.Install(lg, options); err != nil {
+		return
+	}
+	result = &Result{
+		Path:      installed.Path,
+		Directory: filepath.Dir(installed.Path),
+		Version:   installed.Version,
+		Cached:    installed.Cached,
+	}
+	return
+}
+
+// Command returns the terraform-install command, with the flags registered
+// against its own options
+func Command() *commands.Command {
+	return &commands.Command{
+		Name:        "terraform-install",
+		Description: "Download, verify and cache a terraform version.",
+		Flags: func(fs *flag.FlagSet) func(lg *slog.Logger, globals *commands.Globals) (commands.Result, error) {
+			var options = newRunOptions()
+
+			fs.StringVar(&options.Version, "version", options.Version, "Exact terraform version to install, like the resolved output of terraform-version.")
+			fs.StringVar(&options.Mirror, "mirror", options.Mirror, "Base url to download terraform releases from.")
+			fs.StringVar(&options.CacheDirectory, "cache-directory", options.CacheDirectory, "Directory to cache installed terraform versions in.")
+			fs.StringVar(&options.OS, "os", options.OS, "Operating system to install for. Defaults to the current system.")
+			fs.StringVar(&options.Arch, "arch", options.Arch, "Architecture to install for. Defaults to the current system.")
+
+			return func(lg *slog.Logger, globals *commands.Globals) (commands.Result, error) {
+				return Run(lg, options)
+			}
+		},
+	}
+}
@@ -0,0 +1,36 @@
+package terraforminstall
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"opg-github-actions/action/internal/logger"
+	"opg-github-actions/action/internal/terraform"
+	"strings"
+	"testing"
+)
+
+type tiFixture struct {
+	Version       string
+	ErrorContains string
+}
+
+func TestTerraformInstallErrors(t *testing.T) {
+	var (
+		lg     = logger.New("error", "text")
+		server = httptest.NewServer(http.NotFoundHandler())
+	)
+	defer server.Close()
+
+	fixtures := []tiFixture{
+		{Version: "", ErrorContains: ErrMissingVersion.Error()},
+		{Version: ">= 1.6", ErrorContains: "not an exact terraform version"},
+		{Version: "1.6.0", ErrorContains: "failed to download"},
+	}
+
+	for i, f := range fixtures {
+		_, err := Run(lg, &terraform.InstallOptions{Version: f.Version, Mirror: server.URL, CacheDirectory: t.TempDir()})
+		if err == nil || !strings.Contains(err.Error(), f.ErrorContains) {
+			t.Errorf("[%d] expected error containing [%s], actual [%v]", i, f.ErrorContains, err)
+		}
+	}
+}
@@ -0,0 +1,400 @@
+package terraformversion
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"opg-github-actions/action/internal/commands"
+	"opg-github-actions/action/internal/semver"
+	"opg-github-actions/action/internal/terraform"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Errors
+const (
+	ErrFileNotFound      string = "error: file [%s] was not found."
+	ErrNoMatchingVersion string = "error: no available versions match the constraint [%s]"
+	ErrNoRootsFound      string = "error: no required_version found in any terraform files under [%s]"
+	ErrConflictingRoots  string = "error: terraform roots have incompatible version constraints:\n%s"
+	conflictFormat       string = "  - %s [%s] conflicts with %s [%s]"
+)
+
+type Options struct {
+	Directory         string // directory path to operate from
+	File              string // terraform file that contains the required_version property
+	AvailableVersions string // file path or url of the available terraform versions to resolve the constraint against
+	Recursive         bool   // scan all .tf files under the directory rather than a single file
+}
+
+// newRunOptions returns the default options
+func newRunOptions() *Options {
+	return &Options{
+		Directory:         "",
+		File:              "versions.tf",
+		AvailableVersions: "",
+		Recursive:         false,
+	}
+}
+
+// Result contains the versions found and resolved
+type Result struct {
+	Version         string                `json:"version"`          // pinned version or the required_version constraint
+	RequiredVersion string                `json:"required_version"` // required_version constraint from the terraform files
+	Source          string                `json:"source"`           // where the version came from
+	Resolved        string                `json:"resolved"`         // highest available version that matches
+	Min             string                `json:"min"`              // lowest available version that matches
+	Max             string                `json:"max"`              // highest available version that matches
+	Roots           []string              `json:"roots,omitempty"`  // directories with a required_version, only when recursive
+	Providers       []*terraform.Provider `json:"providers"`        // providers from required_providers
+}
+
+// Outputs returns the string values used for github outputs, with the
+// providers as a json encoded list
+func (self *Result) Outputs() (outputs map[string]string) {
+	outputs = map[string]string{}
+	if self == nil {
+		return
+	}
+	providers, _ := json.Marshal(self.Providers)
+	outputs = map[string]string{
+		"version":          self.Version,
+		"required_version": self.RequiredVersion,
+		"source":           self.Source,
+		"resolved":         self.Resolved,
+		"min":              self.Min,
+		"max":              self.Max,
+		"providers":        string(providers),
+	}
+	if self.Roots != nil {
+		outputs["roots"] = strings.Join(self.Roots, ",")
+	}
+	return
+}
+
+// newResult converts the values found into a Result
+func newResult(data map[string]string, providers []*terraform.Provider) *Result {
+	return &Result{
+		Version:         data["version"],
+		RequiredVersion: data["required_version"],
+		Source:          data["source"],
+		Resolved:        data["resolved"],
+		Min:             data["min"],
+		Max:             data["max"],
+		Providers:       providers,
+	}
+}
+
+// FileExists checks if the file exists
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	// if there is an error, or the filepath doesnt exist, return false
+	if err != nil || os.IsNotExist(err) {
+		return false
+	}
+	// return false for directories - its not a file
+	if info.IsDir() {
+		return false
+	}
+
+	return true
+}
+
+// VersionData expects the content of the version file and parses it as hcl
+// to find the `required_version` values of the terraform blocks. Multiple
+// values are joined together as a single constraint.
+//
+// Return example:
+//
+//	map[string]string{ "version": "1.1.0"}
+func versionData(filename string, content []byte) (data map[string]string, err error) {
+	var versions []*terraform.RequiredVersion
+
+	data = map[string]string{}
+	if versions, err = terraform.RequiredVersions(filename, content); err != nil {
+		return
+	}
+	data["version"] = terraform.Constraint(versions)
+	return
+}
+
+// resolve converts the version constraint into a concrete version using
+// the available versions. Returns the highest matching version (`resolved`
+// and `max`) and the lowest matching version (`min`).
+//
+// When there are no available versions, only exact constraints (`1.6.5` or
+// `= 1.6.5`) can be resolved.
+func resolve(constraint string, available []*semver.Semver) (resolved map[string]string, err error) {
+	var (
+		constraints semver.Constraints
+		matching    []*semver.Semver
+	)
+	resolved = map[string]string{"resolved": "", "min": "", "max": ""}
+
+	if constraints, err = semver.ParseConstraints(constraint); err != nil {
+		return
+	}
+	// with no versions to compare against, use the exact version if there is one
+	if len(available) == 0 {
+		if exact := constraints.Exact(); exact != nil {
+			available = []*semver.Semver{exact}
+		} else {
+			return
+		}
+	}
+
+	if matching = constraints.Matching(available); len(matching) == 0 {
+		err = fmt.Errorf(ErrNoMatchingVersion, constraint)
+		return
+	}
+
+	resolved["min"] = matching[0].Stringy(false)
+	resolved["max"] = matching[len(matching)-1].Stringy(false)
+	resolved["resolved"] = resolved["max"]
+	return
+}
+
+// findProviders finds the required providers in each of the directories.
+// In recursive mode each provider records the directory it was found in.
+func findProviders(lg *slog.Logger, base string, directories []string, recursive bool) (all []*terraform.Provider, err error) {
+	all = []*terraform.Provider{}
+
+	for _, dir := range directories {
+		var providers []*terraform.Provider
+		if providers, err = terraform.Providers(lg, dir); err != nil {
+			return
+		}
+		for _, p := range providers {
+			if recursive {
+				p.Directory = relative(base, dir)
+			}
+			all = append(all, p)
+		}
+	}
+	return
+}
+
+// pinData looks for a pinned version (`.terraform-version` or
+// `.tool-versions`) from the directory up to the root of the repository and
+// checks it satisfies the required_version constraint. When found, the pin
+// replaces the constraint as the `version`.
+//
+// Return example:
+//
+//	map[string]string{ "version": "1.6.5", "required_version": "~> 1.6.0", "source": "terraform-version"}
+func pinData(lg *slog.Logger, directory string, constraint string) (data map[string]string, err error) {
+	var pin *terraform.Pin
+	data = map[string]string{
+		"version":          constraint,
+		"required_version": constraint,
+		"source":           string(terraform.SOURCE_REQUIRED_VERSION),
+	}
+
+	if pin, err = terraform.FindPin(lg, directory); err != nil || pin == nil {
+		return
+	}
+	if err = pin.Satisfies(constraint); err != nil {
+		return
+	}
+	data["version"] = pin.Version
+	data["source"] = string(pin.Source)
+	return
+}
+
+// conflicts compares each pair of roots and returns a description of those
+// that have no version in common
+func conflicts(base string, roots []*terraform.Root, constraints []semver.Constraints, candidates []*semver.Semver) (found []string) {
+	found = []string{}
+	for i := 0; i < len(roots); i++ {
+		for j := i + 1; j < len(roots); j++ {
+			var pair = append(semver.Constraints{}, constraints[i]...)
+			pair = append(pair, constraints[j]...)
+			if len(pair.Matching(candidates)) == 0 {
+				found = append(found, fmt.Sprintf(conflictFormat,
+					relative(base, roots[i].Directory), roots[i].Constraint(),
+					relative(base, roots[j].Directory), roots[j].Constraint()))
+			}
+		}
+	}
+	return
+}
+
+// relative returns the path relative to the base, falling back to the path
+func relative(base string, path string) string {
+	if rel, err := filepath.Rel(base, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+// runRecursive scans every terraform file under the directory, checks that
+// the constraints of each root can all be met together and then resolves
+// that combined constraint to a single version.
+//
+// Without available versions, only versions referenced in the constraints
+// are used to resolve the version.
+func runRecursive(lg *slog.Logger, options *Options, available []*semver.Semver) (result *Result, err error) {
+	var (
+		roots       []*terraform.Root
+		resolved    map[string]string
+		providers   []*terraform.Provider
+		pinned      map[string]string
+		data        = map[string]string{}
+		constraints = []semver.Constraints{}
+		combined    = []string{}
+		names       = []string{}
+		directory   = options.Directory
+	)
+	lg = lg.With("operation", "runRecursive", "directory", directory)
+
+	if directory == "" {
+		directory = "."
+	}
+	if roots, err = terraform.Scan(lg, directory); err != nil {
+		return
+	}
+	if len(roots) == 0 {
+		err = fmt.Errorf(ErrNoRootsFound, directory)
+		return
+	}
+
+	for _, root := range roots {
+		var c semver.Constraints
+		if c, err = semver.ParseConstraints(root.Constraint()); err != nil {
+			return
+		}
+		constraints = append(constraints, c)
+		names = append(names, relative(directory, root.Directory))
+		for _, v := range root.Versions {
+			if !slices.Contains(combined, v.Constraint) {
+				combined = append(combined, v.Constraint)
+			}
+		}
+	}
+	data["version"] = strings.Join(combined, ", ")
+
+	dirs := []string{}
+	for _, root := range roots {
+		dirs = append(dirs, root.Directory)
+	}
+	if providers, err = findProviders(lg, directory, dirs, true); err != nil {
+		return
+	}
+
+	// check each root against the others so the report shows where the problem is
+	candidates := available
+	if len(candidates) == 0 {
+		candidates = semver.Candidates(constraints...)
+	}
+	if found := conflicts(directory, roots, constraints, candidates); len(found) > 0 {
+		err = fmt.Errorf(ErrConflictingRoots, strings.Join(found, "\n"))
+		return
+	}
+	// a pinned version has to work for every root
+	if pinned, err = pinData(lg, directory, data["version"]); err != nil {
+		return
+	}
+	for k, v := range pinned {
+		data[k] = v
+	}
+
+	if resolved, err = resolve(data["version"], available); err != nil {
+		return
+	}
+	for k, v := range resolved {
+		data[k] = v
+	}
+	result = newResult(data, providers)
+	result.Roots = names
+	return
+}
+
+// Run processes the input and returns the versions found
+func Run(lg *slog.Logger, options *Options) (result *Result, err error) {
+	var (
+		content   []byte
+		data      map[string]string
+		resolved  map[string]string
+		providers []*terraform.Provider
+		pinned    map[string]string
+		available []*semver.Semver = []*semver.Semver{}
+		file      string           = filepath.Join(options.Directory, options.File)
+	)
+
+	// fetch the versions to compare against
+	if options.AvailableVersions != "" {
+		if available, err = terraform.Versions(lg, options.AvailableVersions); err != nil {
+			return
+		}
+	}
+	if options.Recursive {
+		return runRecursive(lg, options, available)
+	}
+
+	if !fileExists(file) {
+		err = fmt.Errorf(ErrFileNotFound, file)
+		return
+	}
+	// read the file
+	content, err = os.ReadFile(file)
+	if err != nil {
+		return
+	}
+	// pass into the check and return values
+	if data, err = versionData(file, content); err != nil {
+		return
+	}
+	// check for a pinned version near the file
+	if pinned, err = pinData(lg, filepath.Dir(file), data["version"]); err != nil {
+		return
+	}
+	for k, v := range pinned {
+		data[k] = v
+	}
+
+	// now find the concrete version to use
+	if resolved, err = resolve(data["version"], available); err != nil {
+		return
+	}
+	for k, v := range resolved {
+		data[k] = v
+	}
+	// and the providers from the same directory as the file
+	if providers, err = findProviders(lg, options.Directory, []string{filepath.Dir(file)}, false); err != nil {
+		return
+	}
+
+	result = newResult(data, providers)
+	return
+}
+
+// Command returns the terraform-version command, with the flags registered
+// against its own options. The directory comes from the global flags.
+func Command() *commands.Command {
+	return &commands.Command{
+		Name:        "terraform-version",
+		Description: "Find the terraform version from required_version and version files.",
+		Inputs: map[string]string{
+			"terraform_directory":     "directory",
+			"terraform_versions_file": "file",
+			"available_versions":      "available-versions",
+			"recursive":               "recursive",
+		},
+		Flags: func(fs *flag.FlagSet) func(lg *slog.Logger, globals *commands.Globals) (commands.Result, error) {
+			var options = newRunOptions()
+
+			fs.StringVar(&options.File, "file", options.File, "The terraform file that contains the required_version property.")
+			fs.StringVar(&options.AvailableVersions, "available-versions", options.AvailableVersions,
+				fmt.Sprintf("File path or url of available terraform versions to resolve the constraint against, like %s", terraform.DefaultReleasesIndex))
+			fs.BoolVar(&options.Recursive, "recursive", options.Recursive, "Scan all terraform files under the directory and resolve a version that satisfies every root.")
+
+			return func(lg *slog.Logger, globals *commands.Globals) (commands.Result, error) {
+				options.Directory = globals.Directory
+				return Run(lg, options)
+			}
+		},
+	}
+}
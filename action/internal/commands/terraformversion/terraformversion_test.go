@@ -0,0 +1,296 @@
+package terraformversion
+
+import (
+	"opg-github-actions/action/internal/logger"
+	"opg-github-actions/action/internal/semver"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type tvFixture struct {
+	Test     string
+	Expected string
+}
+
+func TestTerraformVersion(t *testing.T) {
+
+	fixtures := []tvFixture{
+		{
+			Test: `terraform {
+				required_version = "1.6.5"
+			}`,
+			Expected: "1.6.5",
+		},
+		{
+			Test:     `terraform {required_version = "1.6.0"}`,
+			Expected: "1.6.0",
+		},
+		{
+			Test:     `terraform {required_version = ">= 1.1.0"}`,
+			Expected: ">= 1.1.0",
+		},
+		{
+			Test: `# required_version = "0.1.0"
+			terraform {
+				required_version = "1.6.0" # a comment
+			}`,
+			Expected: "1.6.0",
+		},
+	}
+
+	for i, f := range fixtures {
+		out, e := versionData("versions.tf", []byte(f.Test))
+		if e != nil {
+			t.Errorf("error: unexpected error for test [%d]: %s", i, e.Error())
+		}
+
+		if out["version"] != f.Expected {
+			t.Errorf("error: expected [%s] actual [%s]", f.Expected, out["version"])
+		}
+	}
+}
+
+type tvResolveFixture struct {
+	Constraint       string
+	Available        []string
+	ShouldError      bool
+	ExpectedResolved string
+	ExpectedMin      string
+	ExpectedMax      string
+}
+
+func TestTerraformVersionResolve(t *testing.T) {
+	var available = []string{"1.4.6", "1.5.0", "1.5.7", "1.6.0-beta1", "1.6.0", "1.6.6", "1.14.3", "2.0.0-alpha1"}
+
+	fixtures := []tvResolveFixture{
+		{Constraint: "~> 1.5.0", Available: available, ExpectedResolved: "1.5.7", ExpectedMin: "1.5.0", ExpectedMax: "1.5.7"},
+		{Constraint: ">= 1.3, < 2.0", Available: available, ExpectedResolved: "1.14.3", ExpectedMin: "1.4.6", ExpectedMax: "1.14.3"},
+		{Constraint: "~> 1.6", Available: available, ExpectedResolved: "1.14.3", ExpectedMin: "1.6.0", ExpectedMax: "1.14.3"},
+		{Constraint: "1.6.0-beta1", Available: available, ExpectedResolved: "1.6.0-beta1", ExpectedMin: "1.6.0-beta1", ExpectedMax: "1.6.0-beta1"},
+		{Constraint: "1.6.5", Available: []string{}, ExpectedResolved: "1.6.5", ExpectedMin: "1.6.5", ExpectedMax: "1.6.5"},
+		{Constraint: ">= 1.6", Available: []string{}, ExpectedResolved: "", ExpectedMin: "", ExpectedMax: ""},
+		{Constraint: "> 3.0", Available: available, ShouldError: true},
+		{Constraint: "latest", Available: available, ShouldError: true},
+	}
+
+	for i, f := range fixtures {
+		versions, _ := semver.FromStrings(f.Available...)
+		out, err := resolve(f.Constraint, versions)
+		if f.ShouldError {
+			if err == nil {
+				t.Errorf("[%d] expected an error, but did not get one", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+		}
+		if out["resolved"] != f.ExpectedResolved {
+			t.Errorf("[%d] resolved expected [%s] actual [%s]", i, f.ExpectedResolved, out["resolved"])
+		}
+		if out["min"] != f.ExpectedMin {
+			t.Errorf("[%d] min expected [%s] actual [%s]", i, f.ExpectedMin, out["min"])
+		}
+		if out["max"] != f.ExpectedMax {
+			t.Errorf("[%d] max expected [%s] actual [%s]", i, f.ExpectedMax, out["max"])
+		}
+	}
+}
+
+type tvRecursiveFixture struct {
+	Files            map[string]string
+	Available        string
+	ErrorContains    string
+	ExpectedVersion  string
+	ExpectedResolved string
+	ExpectedRoots    string
+}
+
+func TestTerraformVersionRecursive(t *testing.T) {
+	var lg = logger.New("error", "text")
+
+	fixtures := []tvRecursiveFixture{
+		{
+			Files: map[string]string{
+				"account/versions.tf":     `terraform { required_version = "~> 1.5.0" }`,
+				"environment/versions.tf": `terraform { required_version = ">= 1.3, < 2.0" }`,
+				"modules/x/versions.tf":   `terraform { required_version = ">= 1.5.2" }`,
+			},
+			ExpectedVersion:  "~> 1.5.0, >= 1.3, < 2.0, >= 1.5.2",
+			ExpectedResolved: "",
+			ExpectedRoots:    "account,environment,modules/x",
+		},
+		// open ended roots without available versions are not resolved, like a single file
+		{
+			Files: map[string]string{
+				"account/versions.tf":     `terraform { required_version = "> 1.5" }`,
+				"environment/versions.tf": `terraform { required_version = "< 2.0" }`,
+			},
+			ExpectedVersion:  "> 1.5, < 2.0",
+			ExpectedResolved: "",
+			ExpectedRoots:    "account,environment",
+		},
+		{
+			Files: map[string]string{
+				"account/versions.tf":     `terraform { required_version = "~> 1.5.0" }`,
+				"environment/versions.tf": `terraform { required_version = ">= 1.3, < 2.0" }`,
+			},
+			Available:        "1.4.0\n1.5.0\n1.5.7\n1.6.0\n",
+			ExpectedVersion:  "~> 1.5.0, >= 1.3, < 2.0",
+			ExpectedResolved: "1.5.7",
+			ExpectedRoots:    "account,environment",
+		},
+		{
+			Files: map[string]string{
+				"account/versions.tf":     `terraform { required_version = "~> 1.5.0" }`,
+				"environment/versions.tf": `terraform { required_version = ">= 1.6" }`,
+				"region/versions.tf":      `terraform { required_version = "> 1.5" }`,
+			},
+			ErrorContains: "account [~> 1.5.0] conflicts with environment [>= 1.6]",
+		},
+		{
+			Files: map[string]string{
+				"modules/x/main.tf": `terraform {}`,
+			},
+			ErrorContains: "no required_version found",
+		},
+	}
+
+	for i, f := range fixtures {
+		var dir = t.TempDir()
+		var options = &Options{Directory: dir, Recursive: true}
+		for name, content := range f.Files {
+			path := filepath.Join(dir, name)
+			os.MkdirAll(filepath.Dir(path), 0755)
+			os.WriteFile(path, []byte(content), 0644)
+		}
+		if f.Available != "" {
+			options.AvailableVersions = filepath.Join(t.TempDir(), "versions.txt")
+			os.WriteFile(options.AvailableVersions, []byte(f.Available), 0644)
+		}
+
+		res, err := Run(lg, options)
+		out := res.Outputs()
+		if f.ErrorContains != "" {
+			if err == nil || !strings.Contains(err.Error(), f.ErrorContains) {
+				t.Errorf("[%d] expected error containing [%s], actual [%v]", i, f.ErrorContains, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+			continue
+		}
+		if out["version"] != f.ExpectedVersion {
+			t.Errorf("[%d] version expected [%s] actual [%s]", i, f.ExpectedVersion, out["version"])
+		}
+		if out["resolved"] != f.ExpectedResolved {
+			t.Errorf("[%d] resolved expected [%s] actual [%s]", i, f.ExpectedResolved, out["resolved"])
+		}
+		if out["roots"] != f.ExpectedRoots {
+			t.Errorf("[%d] roots expected [%s] actual [%s]", i, f.ExpectedRoots, out["roots"])
+		}
+	}
+}
+
+func TestTerraformVersionProviders(t *testing.T) {
+	var (
+		lg       = logger.New("error", "text")
+		dir      = t.TempDir()
+		expected = `[{"name":"aws","source":"hashicorp/aws","version":"6.25.0","locked":"6.25.0","satisfied":true}]`
+	)
+	os.WriteFile(filepath.Join(dir, "versions.tf"), []byte(`terraform {
+		required_providers {
+			aws = { source = "hashicorp/aws", version = "6.25.0" }
+		}
+		required_version = "1.14.3"
+	}`), 0644)
+	os.WriteFile(filepath.Join(dir, ".terraform.lock.hcl"), []byte(`provider "registry.terraform.io/hashicorp/aws" {
+		version = "6.25.0"
+	}`), 0644)
+
+	res, err := Run(lg, &Options{Directory: dir, File: "versions.tf"})
+	out := res.Outputs()
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if out["providers"] != expected {
+		t.Errorf("providers expected [%s] actual [%s]", expected, out["providers"])
+	}
+}
+
+type tvPinFixture struct {
+	Files          map[string]string
+	ErrorContains  string
+	ExpectedSource string
+	Expected       string
+}
+
+func TestTerraformVersionPinned(t *testing.T) {
+	var lg = logger.New("error", "text")
+
+	fixtures := []tvPinFixture{
+		{
+			Files: map[string]string{
+				".terraform-version":    "1.6.5",
+				".tool-versions":        "terraform 1.6.0",
+				"terraform/versions.tf": `terraform { required_version = "~> 1.6.0" }`,
+			},
+			ExpectedSource: "terraform-version",
+			Expected:       "1.6.5",
+		},
+		{
+			Files: map[string]string{
+				"terraform/.tool-versions": "terraform 1.6.0",
+				"terraform/versions.tf":    `terraform { required_version = "~> 1.6.0" }`,
+			},
+			ExpectedSource: "tool-versions",
+			Expected:       "1.6.0",
+		},
+		{
+			Files: map[string]string{
+				"terraform/versions.tf": `terraform { required_version = "~> 1.6.0" }`,
+			},
+			ExpectedSource: "required_version",
+			Expected:       "~> 1.6.0",
+		},
+		{
+			Files: map[string]string{
+				".terraform-version":    "1.7.0",
+				"terraform/versions.tf": `terraform { required_version = "~> 1.6.0" }`,
+			},
+			ErrorContains: "[1.7.0] from",
+		},
+	}
+
+	for i, f := range fixtures {
+		var dir = t.TempDir()
+		os.MkdirAll(filepath.Join(dir, ".git"), 0755)
+		for name, content := range f.Files {
+			path := filepath.Join(dir, name)
+			os.MkdirAll(filepath.Dir(path), 0755)
+			os.WriteFile(path, []byte(content), 0644)
+		}
+
+		res, err := Run(lg, &Options{Directory: filepath.Join(dir, "terraform"), File: "versions.tf"})
+		out := res.Outputs()
+		if f.ErrorContains != "" {
+			if err == nil || !strings.Contains(err.Error(), f.ErrorContains) {
+				t.Errorf("[%d] expected error containing [%s], actual [%v]", i, f.ErrorContains, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+			continue
+		}
+		if out["version"] != f.Expected || out["source"] != f.ExpectedSource {
+			t.Errorf("[%d] expected [%s:%s] actual [%s:%s]", i, f.ExpectedSource, f.Expected, out["source"], out["version"])
+		}
+		if out["required_version"] != "~> 1.6.0" {
+			t.Errorf("[%d] required_version expected [~> 1.6.0] actual [%s]", i, out["required_version"])
+		}
+	}
+}
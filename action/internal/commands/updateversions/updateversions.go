@@ -0,0 +1,223 @@
+package updateversions
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"opg-github-actions/action/internal/commands"
+	"opg-github-actions/action/internal/repo"
+	"opg-github-actions/action/internal/versionfiles"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+var (
+	ErrMissingVersion = errors.New("error: a version is required")
+	ErrMissingFiles   = errors.New("error: at least one version file is required")
+)
+
+// details used for the commit when committing changes
+const (
+	commitAuthorName  string = "github-actions[bot]"
+	commitAuthorEmail string = "41898282+github-actions[bot]@users.noreply.github.com"
+)
+
+type Options struct {
+	Directory     string // directory of the git repo, files are relative to this
+	Version       string // version to write, like the tag output from semver
+	Files         string // comma or new line separated list of `[format:]path[#key]` targets
+	KeepPrefix    bool   // keep the `v` prefix of the version when writing
+	Commit        bool   // commit and push any changed files on the current branch
+	CommitMessage string // message for the commit, `{version}` is replaced
+	TestMode      bool   // report changes without writing files
+	Token         string // github token used to push, when empty GH_TOKEN is used
+}
+
+// newRunOptions returns the default options
+func newRunOptions() *Options {
+	return &Options{
+		Directory:     "",
+		Version:       "",
+		Files:         "",
+		KeepPrefix:    false,
+		Commit:        false,
+		CommitMessage: "Update version files to {version}",
+		TestMode:      false,
+		Token:         "",
+	}
+}
+
+// Result contains the version files checked and the commit, if made
+type Result struct {
+	Version string                 `json:"version"` // version written to the files
+	Files   []*versionfiles.Change `json:"files"`   // each file with its previous version
+	Changed []string               `json:"changed"` // paths of files that changed
+	Commit  string                 `json:"commit"`  // hash of the commit, when made
+	Pushed  bool                   `json:"pushed"`  // if the commit was pushed to the remote
+	Test    bool                   `json:"test"`    // if test mode was enabled
+}
+
+// Outputs returns the string values used for github outputs
+func (self *Result) Outputs() (outputs map[string]string) {
+	outputs = map[string]string{}
+	if self == nil {
+		return
+	}
+	outputs = map[string]string{
+		"version": self.Version,
+		"changed": strings.Join(self.Changed, ","),
+		"commit":  self.Commit,
+		"pushed":  strconv.FormatBool(self.Pushed),
+		"test":    strconv.FormatBool(self.Test),
+	}
+	return
+}
+
+// commitAndPush commits the changed files and pushes them when the repo
+// has a remote
+func commitAndPush(lg *slog.Logger, options *Options, version string, changed []string) (hash plumbing.Hash, pushed bool, err error) {
+	var (
+		repository *git.Repository
+		worktree   *git.Worktree
+		head       *plumbing.Reference
+		remotes    []*git.Remote
+		files      = []string{}
+		auth       = &http.BasicAuth{Username: "opg-github-actions", Password: options.Token}
+	)
+	if auth.Password == "" {
+		auth.Password = os.Getenv("GH_TOKEN")
+	}
+	if repository, err = repo.FromDir(options.Directory); err != nil {
+		return
+	}
+	if worktree, err = repository.Worktree(); err != nil {
+		return
+	}
+	// only commit on a branch, as a commit on a detached HEAD (like a
+	// pull_request checkout) could not be pushed
+	if head, err = repository.Head(); err != nil {
+		return
+	}
+	if !head.Name().IsBranch() {
+		err = fmt.Errorf(repo.ErrDetachedHead)
+		return
+	}
+	// files need to be relative to the root of the repository
+	for _, file := range changed {
+		var abs, rel string
+		if abs, err = filepath.Abs(filepath.Join(options.Directory, file)); err != nil {
+			return
+		}
+		if rel, err = filepath.Rel(worktree.Filesystem.Root(), abs); err != nil {
+			return
+		}
+		files = append(files, filepath.ToSlash(rel))
+	}
+
+	message := strings.ReplaceAll(options.CommitMessage, "{version}", version)
+	author := &object.Signature{Name: commitAuthorName, Email: commitAuthorEmail}
+	if hash, err = repo.Commit(lg, repository, files, message, author); err != nil {
+		return
+	}
+	if remotes, err = repository.Remotes(); err != nil || len(remotes) == 0 {
+		return
+	}
+	if err = repo.PushBranch(lg, repository, auth); err == nil {
+		pushed = true
+	}
+	return
+}
+
+// Run writes the version into each of the version files, only changing
+// the version value so the rest of the file is kept as is.
+//
+// When options.Commit is set, any changed files are committed on the
+// current branch and pushed.
+func Run(lg *slog.Logger, options *Options) (result *Result, err error) {
+	var (
+		targets []*versionfiles.Target
+		hash    plumbing.Hash
+	)
+	lg = lg.With("operation", "updateversions.Run", "version", options.Version)
+
+	if options.Version == "" {
+		err = ErrMissingVersion
+		return
+	}
+	if targets, err = versionfiles.ParseTargets(options.Files); err != nil {
+		return
+	}
+	if len(targets) == 0 {
+		err = ErrMissingFiles
+		return
+	}
+
+	result = &Result{
+		Version: versionfiles.Normalise(options.Version, options.KeepPrefix),
+		Files:   []*versionfiles.Change{},
+		Changed: []string{},
+		Test:    options.TestMode,
+	}
+	for _, target := range targets {
+		var change *versionfiles.Change
+		if change, err = versionfiles.Update(lg, options.Directory, target, result.Version, !options.TestMode); err != nil {
+			return
+		}
+		result.Files = append(result.Files, change)
+		if change.Changed {
+			result.Changed = append(result.Changed, change.Path)
+		}
+	}
+
+	if !options.Commit || options.TestMode || len(result.Changed) == 0 {
+		return
+	}
+	if hash, result.Pushed, err = commitAndPush(lg, options, result.Version, result.Changed); err != nil {
+		return
+	}
+	result.Commit = hash.String()
+	return
+}
+
+// Command returns the update-versions command, with the flags registered
+// against its own options. The repository directory and token come from
+// the global flags.
+func Command() *commands.Command {
+	return &commands.Command{
+		Name:        "update-versions",
+		Description: "Write a version into project version files (package.json, Chart.yaml, pyproject.toml ...).",
+		Inputs: map[string]string{
+			"github_token":   "token",
+			"version":        "version",
+			"files":          "files",
+			"keep_prefix":    "keep-prefix",
+			"commit":         "commit",
+			"commit_message": "commit-message",
+			"test":           "test",
+		},
+		Flags: func(fs *flag.FlagSet) func(lg *slog.Logger, globals *commands.Globals) (commands.Result, error) {
+			var options = newRunOptions()
+
+			fs.StringVar(&options.Version, "version", options.Version, "Version to write into the files, like the tag from semver.")
+			fs.StringVar(&options.Files, "files", options.Files, "Comma or new line separated files to update as [format:]path[#key], like package.json,yaml:chart/Chart.yaml#appVersion. The format is found from the extension when not set.")
+			fs.BoolVar(&options.KeepPrefix, "keep-prefix", options.KeepPrefix, "Keep the prefix (v1.2.3) when writing the version.")
+			fs.BoolVar(&options.Commit, "commit", options.Commit, "Commit and push changed files on the current branch.")
+			fs.StringVar(&options.CommitMessage, "commit-message", options.CommitMessage, "Message for the commit, {version} is replaced with the version.")
+			fs.BoolVar(&options.TestMode, "test", options.TestMode, "Report the changes without writing any files.")
+
+			return func(lg *slog.Logger, globals *commands.Globals) (commands.Result, error) {
+				options.Directory = globals.Directory
+				options.Token = globals.Token
+				return Run(lg, options)
+			}
+		},
+	}
+}
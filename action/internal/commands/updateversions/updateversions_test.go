@@ -0,0 +1,139 @@
+package updateversions
+
+import (
+	"opg-github-actions/action/internal/logger"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// testRepository creates a repo with a committed package.json and VERSION
+// file, both at 1.0.0
+func testRepository(t *testing.T) (dir string, r *git.Repository) {
+	t.Helper()
+	dir = t.TempDir()
+	r, _ = git.PlainInit(dir, false)
+	w, _ := r.Worktree()
+
+	os.MkdirAll(filepath.Join(dir, "app"), 0755)
+	os.WriteFile(filepath.Join(dir, "app", "package.json"), []byte("{\n  \"version\": \"1.0.0\"\n}\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "VERSION"), []byte("1.0.0\n"), 0644)
+	w.Add(".")
+	w.Commit("initial commit", &git.CommitOptions{Author: &object.Signature{Name: "go test", Email: "test@example.com"}})
+	return
+}
+
+type uvFixture struct {
+	Options       *Options
+	Changed       string
+	Committed     bool
+	Expected      string // expected content of app/package.json
+	ErrorContains string
+}
+
+func TestUpdateVersionsRun(t *testing.T) {
+	var lg = logger.New("error", "text")
+	var tests = []*uvFixture{
+		{
+			Options:  &Options{Version: "v1.1.0", Files: "app/package.json, VERSION"},
+			Changed:  "app/package.json,VERSION",
+			Expected: "{\n  \"version\": \"1.1.0\"\n}\n",
+		},
+		{
+			Options:   &Options{Version: "v1.1.0", Files: "app/package.json", KeepPrefix: true, Commit: true},
+			Changed:   "app/package.json",
+			Committed: true,
+			Expected:  "{\n  \"version\": \"v1.1.0\"\n}\n",
+		},
+		// nothing changed, so nothing to commit
+		{
+			Options:  &Options{Version: "1.0.0", Files: "app/package.json\nVERSION", Commit: true},
+			Changed:  "",
+			Expected: "{\n  \"version\": \"1.0.0\"\n}\n",
+		},
+		// test mode reports without writing
+		{
+			Options:  &Options{Version: "2.0.0", Files: "app/package.json", Commit: true, TestMode: true},
+			Changed:  "app/package.json",
+			Expected: "{\n  \"version\": \"1.0.0\"\n}\n",
+		},
+		{Options: &Options{Version: "", Files: "VERSION"}, ErrorContains: ErrMissingVersion.Error()},
+		{Options: &Options{Version: "1.0.0", Files: " , "}, ErrorContains: ErrMissingFiles.Error()},
+		{Options: &Options{Version: "1.0.0", Files: "missing.json"}, ErrorContains: "no such file"},
+	}
+
+	for i, test := range tests {
+		var dir, r = testRepository(t)
+		var options = newRunOptions()
+		options.Directory = dir
+		options.Version = test.Options.Version
+		options.Files = test.Options.Files
+		options.KeepPrefix = test.Options.KeepPrefix
+		options.Commit = test.Options.Commit
+		options.TestMode = test.Options.TestMode
+
+		res, err := Run(lg, options)
+		if test.ErrorContains != "" {
+			if err == nil || !strings.Contains(err.Error(), test.ErrorContains) {
+				t.Errorf("[%d] expected error containing [%s], actual [%v]", i, test.ErrorContains, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+			continue
+		}
+		actual := res.Outputs()
+		if actual["changed"] != test.Changed {
+			t.Errorf("[%d] expected changed [%s], actual [%s]", i, test.Changed, actual["changed"])
+		}
+		content, _ := os.ReadFile(filepath.Join(dir, "app", "package.json"))
+		if string(content) != test.Expected {
+			t.Errorf("[%d] expected:\n%s\nactual:\n%s", i, test.Expected, string(content))
+		}
+
+		head, _ := r.Head()
+		commit, _ := r.CommitObject(head.Hash())
+		if test.Committed {
+			if actual["commit"] != head.Hash().String() || commit.Message != "Update version files to v1.1.0" {
+				t.Errorf("[%d] expected a version commit, actual [%s] [%s]", i, actual["commit"], commit.Message)
+			}
+			if status, _ := mustWorktree(r).Status(); !status.IsClean() {
+				t.Errorf("[%d] expected a clean worktree after commit, actual:\n%s", i, status.String())
+			}
+		} else if actual["commit"] != "" || commit.Message != "initial commit" {
+			t.Errorf("[%d] expected no commit, actual [%s] [%s]", i, actual["commit"], commit.Message)
+		}
+	}
+}
+
+// Test nothing is committed on a detached HEAD, as it could not be pushed
+func TestUpdateVersionsDetachedHead(t *testing.T) {
+	var (
+		lg      = logger.New("error", "text")
+		dir, r  = testRepository(t)
+		head, _ = r.Head()
+		options = newRunOptions()
+	)
+	mustWorktree(r).Checkout(&git.CheckoutOptions{Hash: head.Hash()})
+	options.Directory = dir
+	options.Version = "1.1.0"
+	options.Files = "VERSION"
+	options.Commit = true
+
+	if _, err := Run(lg, options); err == nil || !strings.Contains(err.Error(), "HEAD is not a branch") {
+		t.Errorf("expected detached head error, actual [%v]", err)
+	}
+	if current, _ := r.Head(); current.Hash() != head.Hash() {
+		t.Errorf("expected no commit, actual [%s]", current.Hash().String())
+	}
+}
+
+func mustWorktree(r *git.Repository) *git.Worktree {
+	w, _ := r.Worktree()
+	return w
+}
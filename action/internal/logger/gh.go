@@ -1,9 +1,11 @@
 package logger
 
 import (
+	"crypto/rand"
 	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 )
 
 const (
@@ -13,6 +15,15 @@ const (
 
 // Result provides a wrapper to echo results information out to the stdout for github
 func Result(logger *slog.Logger, results map[string]string) {
+	for k, v := range results {
+		fmt.Printf("%s=%s\n", k, v)
+	}
+	Outputs(logger, results)
+}
+
+// Outputs writes the results to the GITHUB_OUTPUT file when running within
+// a github workspace, without echoing them to stdout
+func Outputs(logger *slog.Logger, results map[string]string) {
 	var outFile string
 	var f *os.File
 	// github output via os.environ['GITHUB_OUTPUT']
@@ -26,11 +37,20 @@ func Result(logger *slog.Logger, results map[string]string) {
 	}
 
 	for k, v := range results {
-		str := fmt.Sprintf("%s=%s\n", k, v)
-		fmt.Print(str)
 		if outFile != "" && f != nil {
 			logger.Debug("writting to GITHUB_OUTPUT")
-			f.WriteString(str)
+			f.WriteString(outputLine(k, v))
 		}
 	}
 }
+
+// outputLine formats the key value pair for the GITHUB_OUTPUT file, using
+// the heredoc style delimiter for multi-line values
+// see: https://docs.github.com/en/actions/reference/workflows-and-actions/workflow-commands#multiline-strings
+func outputLine(k string, v string) string {
+	if !strings.Contains(v, "\n") {
+		return fmt.Sprintf("%s=%s\n", k, v)
+	}
+	delimiter := "ghadelimiter_" + rand.Text()
+	return fmt.Sprintf("%s<<%s\n%s\n%s\n", k, delimiter, strings.TrimSuffix(v, "\n"), delimiter)
+}
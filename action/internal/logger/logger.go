@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"io"
 	"log/slog"
 	"os"
 	"strings"
@@ -14,14 +15,21 @@ import (
 //
 // By default, the level is set to Info and TextHandler
 func New(lvl string, as string) (logger *slog.Logger) {
-	var options = &slog.HandlerOptions{}
-
 	if l := os.Getenv("LOG_LEVEL"); l != "" {
 		lvl = l
 	}
 	if t := os.Getenv("LOG_HANDLER"); t != "" {
 		as = t
 	}
+	return Create(lvl, as, os.Stdout)
+}
+
+// Create returns a slog.Logger writing to w with the log level and
+// handler passed, without checking env values.
+//
+// Unknown levels use Info and unknown handlers use TextHandler
+func Create(lvl string, as string, w io.Writer) (logger *slog.Logger) {
+	var options = &slog.HandlerOptions{}
 
 	switch lvl {
 	case "ERROR", "error":
@@ -38,9 +46,9 @@ func New(lvl string, as string) (logger *slog.Logger) {
 
 	as = strings.ToLower(as)
 	if as == "json" {
-		logger = slog.New(slog.NewJSONHandler(os.Stdout, options))
+		logger = slog.New(slog.NewJSONHandler(w, options))
 	} else {
-		logger = slog.New(slog.NewTextHandler(os.Stdout, options))
+		logger = slog.New(slog.NewTextHandler(w, options))
 	}
 	return
 
@@ -0,0 +1,65 @@
+package notes
+
+import (
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// ChangelogHeader is the title used when creating a new changelog
+const ChangelogHeader string = "# Changelog"
+
+// hasVersion checks if the changelog already has a heading for the version
+func hasVersion(content string, version string) bool {
+	var heading = "## " + version
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == heading || strings.HasPrefix(line, heading+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// Prepend adds the markdown to the top of the changelog file, below its
+// title, creating the file when it does not exist.
+//
+// If the changelog already contains a heading for the version nothing is
+// changed and updated is false, so re-running for the same tag is safe.
+func Prepend(lg *slog.Logger, file string, version string, markdown string) (updated bool, err error) {
+	var (
+		content  []byte
+		existing string
+		title    = ChangelogHeader
+	)
+	lg = lg.With("operation", "Prepend", "file", file, "version", version)
+
+	if content, err = os.ReadFile(file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return
+	}
+	err = nil
+	existing = string(content)
+
+	if hasVersion(existing, version) {
+		lg.Info("changelog already contains version, skipping ... ")
+		return
+	}
+	// keep the existing title at the top of the file
+	if first, rest, _ := strings.Cut(existing, "\n"); strings.HasPrefix(first, "# ") {
+		title = first
+		existing = rest
+	}
+	existing = strings.TrimLeft(existing, "\n")
+
+	content = []byte(title + "\n\n" + strings.TrimRight(markdown, "\n") + "\n")
+	if existing != "" {
+		content = append(content, []byte("\n"+existing)...)
+	}
+	if err = os.WriteFile(file, content, 0644); err != nil {
+		return
+	}
+	updated = true
+	return
+}
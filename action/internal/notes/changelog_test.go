@@ -0,0 +1,53 @@
+package notes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"opg-github-actions/action/internal/logger"
+)
+
+type prependFixture struct {
+	Existing string
+	Version  string
+	Markdown string
+	Expected string
+	Updated  bool
+}
+
+func TestChangelogPrepend(t *testing.T) {
+	var lg = logger.New("error", "text")
+	var tests = []*prependFixture{
+		// new file
+		{Existing: "", Version: "v1.0.0", Markdown: "## v1.0.0\n\n- first\n", Updated: true,
+			Expected: "# Changelog\n\n## v1.0.0\n\n- first\n"},
+		// keeps the title of an existing file
+		{Existing: "# Project changes\n\n## v1.0.0\n\n- first\n", Version: "v1.1.0", Markdown: "## v1.1.0\n\n- second\n", Updated: true,
+			Expected: "# Project changes\n\n## v1.1.0\n\n- second\n\n## v1.0.0\n\n- first\n"},
+		// file without a title
+		{Existing: "## v1.0.0\n\n- first\n", Version: "v1.1.0", Markdown: "## v1.1.0\n\n- second\n", Updated: true,
+			Expected: "# Changelog\n\n## v1.1.0\n\n- second\n\n## v1.0.0\n\n- first\n"},
+		// version already present
+		{Existing: "# Changelog\n\n## v1.1.0 (2026-01-01)\n\n- second\n", Version: "v1.1.0", Markdown: "## v1.1.0\n\n- other\n", Updated: false,
+			Expected: "# Changelog\n\n## v1.1.0 (2026-01-01)\n\n- second\n"},
+	}
+
+	for i, test := range tests {
+		var file = filepath.Join(t.TempDir(), "CHANGELOG.md")
+		if test.Existing != "" {
+			os.WriteFile(file, []byte(test.Existing), 0644)
+		}
+		updated, err := Prepend(lg, file, test.Version, test.Markdown)
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+		}
+		if updated != test.Updated {
+			t.Errorf("[%d] expected updated [%t], actual [%t]", i, test.Updated, updated)
+		}
+		actual, _ := os.ReadFile(file)
+		if string(actual) != test.Expected {
+			t.Errorf("[%d] expected:\n%s\nactual:\n%s", i, test.Expected, string(actual))
+		}
+	}
+}
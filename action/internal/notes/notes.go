@@ -0,0 +1,244 @@
+package notes
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"opg-github-actions/action/internal/semver"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Group is the section of the release notes a commit is listed under
+type Group string
+
+const (
+	GROUP_BREAKING Group = "breaking"
+	GROUP_FEATURES Group = "features"
+	GROUP_FIXES    Group = "fixes"
+	GROUP_OTHER    Group = "other"
+)
+
+// Groups in the order they are rendered
+var Groups = []Group{GROUP_BREAKING, GROUP_FEATURES, GROUP_FIXES, GROUP_OTHER}
+
+// Title returns the heading used for the group
+func (self Group) Title() string {
+	switch self {
+	case GROUP_BREAKING:
+		return "Breaking changes"
+	case GROUP_FEATURES:
+		return "Features"
+	case GROUP_FIXES:
+		return "Fixes"
+	}
+	return "Other changes"
+}
+
+// Regex patterns used to parse commit messages
+//   - conventional commits: https://www.conventionalcommits.org/en/v1.0.0/
+//   - merge commits and squashed pull requests as generated by github
+//   - semver triggers (#major / !minor etc) are removed from the subject
+var (
+	regexConventional = regexp.MustCompile(`^(?P<type>[a-zA-Z]+)(?:\((?P<scope>[^)]*)\))?(?P<breaking>!)?:\s+(?P<subject>.+)$`)
+	regexMergePR      = regexp.MustCompile(`^Merge pull request #(?P<pr>\d+) from \S+`)
+	regexSquashPR     = regexp.MustCompile(`\s*\(#(?P<pr>\d+)\)$`)
+	regexBreaking     = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE:`)
+	regexTriggers     = regexp.MustCompile(`\s*[#!](?:major|minor|patch)\b`)
+	regexNoReply      = regexp.MustCompile(`^(?:\d+\+)?(?P<user>[^@]+)@users\.noreply\.github\.com$`)
+)
+
+// Entry is a single commit within the release notes
+type Entry struct {
+	Hash        string    `json:"hash"`
+	Subject     string    `json:"subject"`      // first line of the commit, without the type, triggers or pr number
+	Type        string    `json:"type"`         // conventional commit type (feat, fix ...)
+	Scope       string    `json:"scope"`        // conventional commit scope
+	Group       Group     `json:"group"`        // section the entry is listed under
+	Author      string    `json:"author"`       // @username for github noreply emails, otherwise the name
+	PullRequest int       `json:"pull_request"` // pull request number, 0 when not known
+	When        time.Time `json:"when"`
+}
+
+// Author returns the github username (as @username) when the email is a
+// github noreply address, otherwise the name is returned
+func Author(name string, email string) string {
+	if m := regexNoReply.FindStringSubmatch(email); len(m) > 0 {
+		return "@" + m[regexNoReply.SubexpIndex("user")]
+	}
+	return name
+}
+
+// Parse converts the commit message into an entry.
+//
+// The group is decided by the semver triggers first and then the
+// conventional commit type:
+//   - #major, a `!` after the type or a BREAKING CHANGE footer => breaking
+//   - #minor or `feat` => features
+//   - #patch or `fix` => fixes
+//   - anything else => other
+func Parse(message string) (entry *Entry) {
+	var (
+		lines   = strings.Split(strings.TrimSpace(message), "\n")
+		subject = strings.TrimSpace(lines[0])
+		body    = lines[1:]
+	)
+	entry = &Entry{Group: GROUP_OTHER}
+
+	// merge commits put the pull request title on the first line of the body
+	if m := regexMergePR.FindStringSubmatch(subject); len(m) > 0 {
+		entry.PullRequest, _ = strconv.Atoi(m[regexMergePR.SubexpIndex("pr")])
+		for _, line := range body {
+			if line = strings.TrimSpace(line); line != "" {
+				subject = line
+				break
+			}
+		}
+	}
+	if m := regexSquashPR.FindStringSubmatch(subject); len(m) > 0 {
+		entry.PullRequest, _ = strconv.Atoi(m[regexSquashPR.SubexpIndex("pr")])
+		subject = regexSquashPR.ReplaceAllString(subject, "")
+	}
+
+	breaking := regexBreaking.MatchString(message)
+	if m := regexConventional.FindStringSubmatch(subject); len(m) > 0 {
+		entry.Type = strings.ToLower(m[regexConventional.SubexpIndex("type")])
+		entry.Scope = m[regexConventional.SubexpIndex("scope")]
+		subject = m[regexConventional.SubexpIndex("subject")]
+		breaking = breaking || m[regexConventional.SubexpIndex("breaking")] != ""
+	}
+	entry.Subject = strings.TrimSpace(regexTriggers.ReplaceAllString(subject, ""))
+
+	switch {
+	case breaking || strings.Contains(message, semver.MAJOR.Stringy()):
+		entry.Group = GROUP_BREAKING
+	case entry.Type == "feat" || strings.Contains(message, semver.MINOR.Stringy()):
+		entry.Group = GROUP_FEATURES
+	case entry.Type == "fix" || strings.Contains(message, semver.PATCH.Stringy()):
+		entry.Group = GROUP_FIXES
+	}
+	return
+}
+
+// FromCommits converts the commits into entries, newest first. Merge
+// commits that are not from a pull request (like merging main into a
+// branch) are skipped.
+func FromCommits(lg *slog.Logger, commits []*object.Commit) (entries []*Entry) {
+	lg = lg.With("operation", "FromCommits")
+	entries = []*Entry{}
+
+	for _, commit := range commits {
+		if commit.NumParents() > 1 && !regexMergePR.MatchString(commit.Message) {
+			lg.Debug("skipping merge commit ... ", "hash", commit.Hash.String())
+			continue
+		}
+		entry := Parse(commit.Message)
+		entry.Hash = commit.Hash.String()
+		entry.Author = Author(commit.Author.Name, commit.Author.Email)
+		entry.When = commit.Committer.When
+		entries = append(entries, entry)
+	}
+
+	slices.SortStableFunc(entries, func(a, b *Entry) int {
+		if c := b.When.Compare(a.When); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Hash, b.Hash)
+	})
+	return
+}
+
+// PreviousRelease returns the last release before the version passed, or
+// the last release overall when version is not a semver
+func PreviousRelease(lg *slog.Logger, existing []*semver.Semver, version string) (previous *semver.Semver) {
+	var (
+		current = semver.FromString(version)
+		before  = []*semver.Semver{}
+	)
+	for _, s := range existing {
+		if current == nil || semver.Compare(s, current) < 0 {
+			before = append(before, s)
+		}
+	}
+	previous = semver.GetLastRelease(lg, before)
+	return
+}
+
+// Notes are the grouped entries for a version
+type Notes struct {
+	Version       string    // heading for the notes, typically the tag
+	Date          time.Time // date shown alongside the version
+	RepositoryURL string    // used to link pull requests and commits, like https://github.com/owner/name
+	Entries       []*Entry
+}
+
+// Group returns the entries within the group
+func (self *Notes) Group(group Group) (entries []*Entry) {
+	entries = []*Entry{}
+	for _, e := range self.Entries {
+		if e.Group == group {
+			entries = append(entries, e)
+		}
+	}
+	return
+}
+
+// line renders the entry as a markdown list item
+func (self *Notes) line(e *Entry) (line string) {
+	line = "- "
+	if e.Scope != "" {
+		line += fmt.Sprintf("**%s:** ", e.Scope)
+	}
+	line += e.Subject
+
+	short := e.Hash
+	if len(short) > 7 {
+		short = short[:7]
+	}
+	switch {
+	case e.PullRequest > 0 && self.RepositoryURL != "":
+		line += fmt.Sprintf(" ([#%d](%s/pull/%d))", e.PullRequest, self.RepositoryURL, e.PullRequest)
+	case e.PullRequest > 0:
+		line += fmt.Sprintf(" (#%d)", e.PullRequest)
+	case short != "" && self.RepositoryURL != "":
+		line += fmt.Sprintf(" ([%s](%s/commit/%s))", short, self.RepositoryURL, e.Hash)
+	case short != "":
+		line += fmt.Sprintf(" (%s)", short)
+	}
+	if e.Author != "" {
+		line += " by " + e.Author
+	}
+	return
+}
+
+// Markdown renders the notes with a heading for the version and a section
+// for each group that has entries
+func (self *Notes) Markdown() string {
+	var sb = &strings.Builder{}
+
+	fmt.Fprintf(sb, "## %s", self.Version)
+	if !self.Date.IsZero() {
+		fmt.Fprintf(sb, " (%s)", self.Date.Format(time.DateOnly))
+	}
+	sb.WriteString("\n")
+
+	if len(self.Entries) == 0 {
+		sb.WriteString("\nNo changes.\n")
+	}
+	for _, group := range Groups {
+		var entries = self.Group(group)
+		if len(entries) == 0 {
+			continue
+		}
+		fmt.Fprintf(sb, "\n### %s\n\n", group.Title())
+		for _, e := range entries {
+			sb.WriteString(self.line(e) + "\n")
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,89 @@
+package notes
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type parseFixture struct {
+	Message     string
+	Group       Group
+	Subject     string
+	Type        string
+	Scope       string
+	PullRequest int
+}
+
+func TestNotesParse(t *testing.T) {
+	var tests = []*parseFixture{
+		{Message: "update readme", Group: GROUP_OTHER, Subject: "update readme"},
+		{Message: "add new input #minor", Group: GROUP_FEATURES, Subject: "add new input"},
+		{Message: "remove old input #major\n\nsome detail", Group: GROUP_BREAKING, Subject: "remove old input"},
+		{Message: "correct typo #patch (#42)", Group: GROUP_FIXES, Subject: "correct typo", PullRequest: 42},
+		{Message: "feat(release): upload artifacts", Group: GROUP_FEATURES, Subject: "upload artifacts", Type: "feat", Scope: "release"},
+		{Message: "fix: handle empty tags", Group: GROUP_FIXES, Subject: "handle empty tags", Type: "fix"},
+		{Message: "refactor!: rename outputs", Group: GROUP_BREAKING, Subject: "rename outputs", Type: "refactor"},
+		{Message: "feat: new flag\n\nBREAKING CHANGE: old flag removed", Group: GROUP_BREAKING, Subject: "new flag", Type: "feat"},
+		{Message: "chore(deps): bump go-github (#7)", Group: GROUP_OTHER, Subject: "bump go-github", Type: "chore", Scope: "deps", PullRequest: 7},
+		{Message: "Merge pull request #12 from owner/my-branch\n\nfeat: release notes #minor", Group: GROUP_FEATURES, Subject: "release notes", Type: "feat", PullRequest: 12},
+		// triggers win over the conventional type
+		{Message: "fix: change output format #major", Group: GROUP_BREAKING, Subject: "change output format", Type: "fix"},
+	}
+
+	for i, test := range tests {
+		actual := Parse(test.Message)
+		if actual.Group != test.Group {
+			t.Errorf("[%d] expected group [%s], actual [%s]", i, test.Group, actual.Group)
+		}
+		if actual.Subject != test.Subject || actual.Type != test.Type || actual.Scope != test.Scope {
+			t.Errorf("[%d] expected [%s] [%s] [%s], actual [%s] [%s] [%s]", i, test.Subject, test.Type, test.Scope, actual.Subject, actual.Type, actual.Scope)
+		}
+		if actual.PullRequest != test.PullRequest {
+			t.Errorf("[%d] expected pull request [%d], actual [%d]", i, test.PullRequest, actual.PullRequest)
+		}
+	}
+}
+
+func TestNotesAuthor(t *testing.T) {
+	var tests = map[[2]string]string{
+		{"A Person", "12345+aperson@users.noreply.github.com"}: "@aperson",
+		{"A Person", "aperson@users.noreply.github.com"}:       "@aperson",
+		{"A Person", "a.person@example.com"}:                   "A Person",
+	}
+	for in, expected := range tests {
+		if actual := Author(in[0], in[1]); actual != expected {
+			t.Errorf("expected [%s], actual [%s]", expected, actual)
+		}
+	}
+}
+
+func TestNotesMarkdown(t *testing.T) {
+	var notes = &Notes{
+		Version:       "v1.2.0",
+		Date:          time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+		RepositoryURL: "https://github.com/owner/repo",
+		Entries: []*Entry{
+			{Hash: "aaaaaaaaaa", Subject: "new flag", Scope: "cli", Group: GROUP_FEATURES, Author: "@aperson", PullRequest: 3},
+			{Hash: "bbbbbbbbbb", Subject: "typo", Group: GROUP_FIXES, Author: "A Person"},
+		},
+	}
+	var expected = `## v1.2.0 (2026-10-19)
+
+### Features
+
+- **cli:** new flag ([#3](https://github.com/owner/repo/pull/3)) by @aperson
+
+### Fixes
+
+- typo ([bbbbbbb](https://github.com/owner/repo/commit/bbbbbbbbbb)) by A Person
+`
+	if actual := notes.Markdown(); actual != expected {
+		t.Errorf("expected:\n%s\nactual:\n%s", expected, actual)
+	}
+
+	notes.Entries = []*Entry{}
+	if actual := notes.Markdown(); !strings.Contains(actual, "No changes.") {
+		t.Errorf("expected empty notes to say there are no changes, actual:\n%s", actual)
+	}
+}
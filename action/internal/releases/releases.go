@@ -0,0 +1,249 @@
+package releases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v74/github"
+)
+
+// DefaultAPIURL is the public github api, GITHUB_API_URL is used in
+// preference when set
+const DefaultAPIURL string = "https://api.github.com/"
+
+const (
+	ErrInvalidRepository string = "error: repository [%s] should be in the form owner/name"
+	ErrInvalidLatest     string = "error: latest [%s] should be empty, true or false"
+	ErrInvalidNotes      string = "error: notes [%s] should be one of %s or %s"
+	ErrTagNotFound       string = "error: tag [%s] does not exist in the remote repository"
+	ErrNoArtifacts       string = "error: no artifacts match the pattern [%s]"
+	ErrUploadFailed      string = "error: failed to upload artifact [%s]: %s"
+)
+
+// Notes is how the body of the release is generated, the values match the
+// flags of `gh release create` that the action used to call
+type Notes string
+
+const (
+	NOTES_FROM_TAG Notes = "--notes-from-tag" // use the annotated tag message
+	NOTES_GENERATE Notes = "--generate-notes" // use githubs generated release notes
+)
+
+// Repository is the owner and name of a github repository
+type Repository struct {
+	Owner string
+	Name  string
+}
+
+// ParseRepository splits a `owner/name` string, like GITHUB_REPOSITORY,
+// into its parts
+func ParseRepository(s string) (repository *Repository, err error) {
+	var owner, name, found = strings.Cut(s, "/")
+	if !found || owner == "" || name == "" || strings.Contains(name, "/") {
+		err = fmt.Errorf(ErrInvalidRepository, s)
+		return
+	}
+	repository = &Repository{Owner: owner, Name: name}
+	return
+}
+
+// UploadURLFor returns the url assets are uploaded to for a github
+// enterprise server api url (`https://github.example.com/api/v3` =>
+// `https://github.example.com/api/uploads`). Other urls are returned as
+// they are, which github.Client.WithEnterpriseURLs adds `api/uploads/` to.
+func UploadURLFor(apiURL string) string {
+	return strings.Replace(apiURL, "/api/v3", "/api/uploads", 1)
+}
+
+// NewClient returns a github client for the api url. For DefaultAPIURL (or
+// an empty url) the go-github defaults are used, which upload assets to
+// uploads.github.com; otherwise the urls are treated as github enterprise
+// server. When uploadURL is empty it is found from the api url (see
+// UploadURLFor).
+func NewClient(token string, apiURL string, uploadURL string) (client *github.Client, err error) {
+	client = github.NewClient(nil)
+	if token != "" {
+		client = client.WithAuthToken(token)
+	}
+	if apiURL == "" || strings.TrimSuffix(apiURL, "/") == strings.TrimSuffix(DefaultAPIURL, "/") {
+		return
+	}
+	if uploadURL == "" {
+		uploadURL = UploadURLFor(apiURL)
+	}
+	client, err = client.WithEnterpriseURLs(apiURL, uploadURL)
+	return
+}
+
+// MakeLatest returns the make_latest value for a release using the same
+// rules as the action; when latest is set it is used, otherwise only
+// full releases are marked as latest.
+func MakeLatest(latest string, prerelease bool) (makeLatest string, err error) {
+	var isLatest bool
+
+	if latest == "" {
+		makeLatest = strconv.FormatBool(!prerelease)
+		return
+	}
+	if isLatest, err = strconv.ParseBool(latest); err != nil {
+		err = fmt.Errorf(ErrInvalidLatest, latest)
+		return
+	}
+	makeLatest = strconv.FormatBool(isLatest)
+	return
+}
+
+// ValidNotes checks the notes value is one of the known types
+func ValidNotes(notes Notes) (err error) {
+	if notes != NOTES_FROM_TAG && notes != NOTES_GENERATE {
+		err = fmt.Errorf(ErrInvalidNotes, notes, NOTES_FROM_TAG, NOTES_GENERATE)
+	}
+	return
+}
+
+// isNotFound checks if the api error was a 404
+func isNotFound(err error) bool {
+	var respErr *github.ErrorResponse
+	return errors.As(err, &respErr) && respErr.Response != nil && respErr.Response.StatusCode == http.StatusNotFound
+}
+
+// findInList pages through all releases for one with the tag, as draft
+// releases are only returned by the list endpoint
+func findInList(ctx context.Context, client *github.Client, repository *Repository, tag string) (release *github.RepositoryRelease, err error) {
+	var (
+		list []*github.RepositoryRelease
+		resp *github.Response
+		opts = &github.ListOptions{PerPage: 100}
+	)
+	for {
+		if list, resp, err = client.Repositories.ListReleases(ctx, repository.Owner, repository.Name, opts); err != nil {
+			return
+		}
+		for _, r := range list {
+			if r.GetTagName() == tag {
+				release = r
+				return
+			}
+		}
+		if resp.NextPage == 0 {
+			return
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// Find returns the release for the tag, including drafts. When prerelease
+// is set the release must also be a prerelease to count as found, matching
+// how the action filtered the release list.
+//
+// Published releases are found by tag; when there is not one the release
+// list is checked for a draft with the tag, so a draft from an earlier run
+// is reused rather than duplicated.
+//
+// A nil release without an error means no release matched.
+func Find(ctx context.Context, lg *slog.Logger, client *github.Client, repository *Repository, tag string, prerelease bool) (release *github.RepositoryRelease, err error) {
+	lg = lg.With("operation", "Find", "tag", tag, "prerelease", prerelease)
+
+	release, _, err = client.Repositories.GetReleaseByTag(ctx, repository.Owner, repository.Name, tag)
+	if isNotFound(err) {
+		lg.Debug("no published release found for tag, checking drafts ... ")
+		if release, err = findInList(ctx, client, repository, tag); err != nil || release == nil {
+			return
+		}
+	} else if err != nil {
+		return
+	}
+
+	if prerelease && !release.GetPrerelease() {
+		lg.Debug("release found, but it is not a prerelease ... ")
+		release = nil
+	}
+	return
+}
+
+// TagMessage returns the message of an annotated tag, returning an error
+// when the tag does not exist. Lightweight tags have no message, so an
+// empty string is returned for them.
+func TagMessage(ctx context.Context, lg *slog.Logger, client *github.Client, repository *Repository, tag string) (message string, err error) {
+	var (
+		ref       *github.Reference
+		annotated *github.Tag
+	)
+	lg = lg.With("operation", "TagMessage", "tag", tag)
+
+	ref, _, err = client.Git.GetRef(ctx, repository.Owner, repository.Name, "tags/"+tag)
+	if isNotFound(err) {
+		err = fmt.Errorf(ErrTagNotFound, tag)
+		return
+	} else if err != nil {
+		return
+	}
+
+	if ref.GetObject().GetType() != "tag" {
+		lg.Debug("lightweight tag, no message ... ")
+		return
+	}
+	if annotated, _, err = client.Git.GetTag(ctx, repository.Owner, repository.Name, ref.GetObject().GetSHA()); err != nil {
+		return
+	}
+	message = annotated.GetMessage()
+	return
+}
+
+// Artifacts expands the whitespace separated glob patterns relative to the
+// directory and returns the matching files. Each pattern must match at
+// least one file.
+func Artifacts(directory string, patterns string) (files []string, err error) {
+	files = []string{}
+
+	for _, pattern := range strings.Fields(patterns) {
+		var matches []string
+
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(directory, pattern)
+		}
+		if matches, err = filepath.Glob(pattern); err != nil {
+			return
+		}
+		if len(matches) == 0 {
+			err = fmt.Errorf(ErrNoArtifacts, pattern)
+			return
+		}
+		files = append(files, matches...)
+	}
+	return
+}
+
+// Upload attaches each file to the release as an asset named after the
+// file, returning the asset names
+func Upload(ctx context.Context, lg *slog.Logger, client *github.Client, repository *Repository, release *github.RepositoryRelease, files []string) (uploaded []string, err error) {
+	lg = lg.With("operation", "Upload", "release", release.GetID())
+	uploaded = []string{}
+
+	for _, file := range files {
+		var (
+			fp   *os.File
+			name = filepath.Base(file)
+		)
+		lg.Debug("uploading artifact ... ", "file", file)
+
+		if fp, err = os.Open(file); err != nil {
+			return
+		}
+		_, _, err = client.Repositories.UploadReleaseAsset(ctx, repository.Owner, repository.Name, release.GetID(), &github.UploadOptions{Name: name}, fp)
+		fp.Close()
+		if err != nil {
+			err = fmt.Errorf(ErrUploadFailed, file, err.Error())
+			return
+		}
+		uploaded = append(uploaded, name)
+	}
+	return
+}
@@ -0,0 +1,137 @@
+package releases
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type latestFixture struct {
+	Latest        string
+	Prerelease    bool
+	Expected      string
+	ErrorContains string
+}
+
+func TestReleasesMakeLatest(t *testing.T) {
+	var tests = []*latestFixture{
+		{Latest: "", Prerelease: false, Expected: "true"},
+		{Latest: "", Prerelease: true, Expected: "false"},
+		{Latest: "true", Prerelease: true, Expected: "true"},
+		{Latest: "false", Prerelease: false, Expected: "false"},
+		{Latest: "yes", Prerelease: false, ErrorContains: "should be empty, true or false"},
+	}
+
+	for i, test := range tests {
+		actual, err := MakeLatest(test.Latest, test.Prerelease)
+		if test.ErrorContains != "" {
+			if err == nil || !strings.Contains(err.Error(), test.ErrorContains) {
+				t.Errorf("[%d] expected error containing [%s], actual [%v]", i, test.ErrorContains, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+		}
+		if actual != test.Expected {
+			t.Errorf("[%d] expected [%s], actual [%s]", i, test.Expected, actual)
+		}
+	}
+}
+
+type repositoryFixture struct {
+	Value    string
+	Expected *Repository
+}
+
+func TestReleasesParseRepository(t *testing.T) {
+	var tests = []*repositoryFixture{
+		{Value: "ministryofjustice/opg-github-actions", Expected: &Repository{Owner: "ministryofjustice", Name: "opg-github-actions"}},
+		{Value: "owner/", Expected: nil},
+		{Value: "/name", Expected: nil},
+		{Value: "name", Expected: nil},
+		{Value: "owner/name/extra", Expected: nil},
+	}
+
+	for i, test := range tests {
+		actual, err := ParseRepository(test.Value)
+		if test.Expected == nil {
+			if err == nil {
+				t.Errorf("[%d] expected an error for [%s]", i, test.Value)
+			}
+			continue
+		}
+		if err != nil || *actual != *test.Expected {
+			t.Errorf("[%d] expected [%v], actual [%v] [%v]", i, test.Expected, actual, err)
+		}
+	}
+}
+
+type artifactsFixture struct {
+	Patterns      string
+	Expected      []string
+	ErrorContains string
+}
+
+func TestReleasesArtifacts(t *testing.T) {
+	var dir = t.TempDir()
+	for _, f := range []string{"build/a.zip", "build/b.zip", "build/notes.txt", "c.tar"} {
+		os.MkdirAll(filepath.Join(dir, filepath.Dir(f)), 0755)
+		os.WriteFile(filepath.Join(dir, f), []byte(f), 0644)
+	}
+
+	var tests = []*artifactsFixture{
+		{Patterns: "", Expected: []string{}},
+		{Patterns: "build/*.zip", Expected: []string{"a.zip", "b.zip"}},
+		{Patterns: "build/*.zip  c.tar", Expected: []string{"a.zip", "b.zip", "c.tar"}},
+		{Patterns: "build/*.zip missing/*", ErrorContains: "no artifacts match"},
+	}
+
+	for i, test := range tests {
+		files, err := Artifacts(dir, test.Patterns)
+		if test.ErrorContains != "" {
+			if err == nil || !strings.Contains(err.Error(), test.ErrorContains) {
+				t.Errorf("[%d] expected error containing [%s], actual [%v]", i, test.ErrorContains, err)
+			}
+			continue
+		}
+		var actual = []string{}
+		for _, f := range files {
+			actual = append(actual, filepath.Base(f))
+		}
+		if err != nil || !slices.Equal(actual, test.Expected) {
+			t.Errorf("[%d] expected [%v], actual [%v] [%v]", i, test.Expected, actual, err)
+		}
+	}
+}
+
+type clientFixture struct {
+	APIURL    string
+	UploadURL string
+	Base      string
+	Uploads   string
+}
+
+func TestReleasesNewClient(t *testing.T) {
+	var tests = []*clientFixture{
+		// github.com uses the go-github defaults
+		{APIURL: "", Base: "https://api.github.com/", Uploads: "https://uploads.github.com/"},
+		{APIURL: "https://api.github.com", Base: "https://api.github.com/", Uploads: "https://uploads.github.com/"},
+		// github enterprise server
+		{APIURL: "https://github.example.com/api/v3", Base: "https://github.example.com/api/v3/", Uploads: "https://github.example.com/api/uploads/"},
+		{APIURL: "https://github.example.com/api/v3", UploadURL: "https://uploads.example.com/api/uploads/", Base: "https://github.example.com/api/v3/", Uploads: "https://uploads.example.com/api/uploads/"},
+	}
+
+	for i, test := range tests {
+		client, err := NewClient("", test.APIURL, test.UploadURL)
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+			continue
+		}
+		if client.BaseURL.String() != test.Base || client.UploadURL.String() != test.Uploads {
+			t.Errorf("[%d] expected [%s, %s], actual [%s, %s]", i, test.Base, test.Uploads, client.BaseURL.String(), client.UploadURL.String())
+		}
+	}
+}
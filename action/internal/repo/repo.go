@@ -5,13 +5,19 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"time"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 )
 
-const ErrDirectoryNotFound string = "Directory not found: [%s]"
+const (
+	ErrDirectoryNotFound string = "Directory not found: [%s]"
+	ErrDetachedHead      string = "error: HEAD is not a branch, so changes cannot be pushed"
+)
 
 // directoryExists checks if the path exists and is a directory
 func directoryExists(path string) (exists bool) {
@@ -123,3 +129,52 @@ func Fetch(lg *slog.Logger, r *git.Repository, auth *http.BasicAuth) (err error)
 	}
 	return
 }
+
+// Commit adds the files (relative to the root of the repository) to the
+// index and commits them on the current branch, returning the new commit
+func Commit(lg *slog.Logger, r *git.Repository, files []string, message string, author *object.Signature) (hash plumbing.Hash, err error) {
+	var w *git.Worktree
+	lg = lg.With("operation", "Commit", "files", files)
+
+	if w, err = r.Worktree(); err != nil {
+		return
+	}
+	for _, file := range files {
+		lg.Debug("adding file ... ", "file", file)
+		if _, err = w.Add(file); err != nil {
+			return
+		}
+	}
+	if author.When.IsZero() {
+		author.When = time.Now()
+	}
+	lg.Info("committing files ... ")
+	hash, err = w.Commit(message, &git.CommitOptions{Author: author, Committer: author})
+	return
+}
+
+// PushBranch pushes the current branch to the origin remote
+func PushBranch(lg *slog.Logger, r *git.Repository, auth *http.BasicAuth) (err error) {
+	var head *plumbing.Reference
+	lg = lg.With("operation", "PushBranch")
+
+	if head, err = r.Head(); err != nil {
+		return
+	}
+	if !head.Name().IsBranch() {
+		err = fmt.Errorf(ErrDetachedHead)
+		return
+	}
+	lg.Info("pushing branch ... ", "branch", head.Name().Short())
+	err = r.Push(&git.PushOptions{
+		RemoteName: "origin",
+		RefSpecs:   []config.RefSpec{config.RefSpec(head.Name().String() + ":" + head.Name().String())},
+		Auth:       auth,
+	})
+	// skip up to date error
+	if errors.Is(err, git.NoErrAlreadyUpToDate) {
+		lg.Warn("warning from pushing branch: already up-to-date")
+		err = nil
+	}
+	return
+}
@@ -0,0 +1,227 @@
+package semver
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCalVerFormat is the calendar version format used when none is set,
+// generating versions like `2026.10.3`
+const DefaultCalVerFormat string = "YYYY.0M.MICRO"
+
+const ErrInvalidCalVerFormat string = "error: calver format [%s] is not valid: %s"
+
+// calverSeparators are the only literal characters allowed in a format
+const calverSeparators string = ".-_"
+
+// calverToken is a placeholder within a calendar version format, see
+// https://calver.org/#scheme
+type calverToken struct {
+	Name    string
+	Pattern string                   // regex for matching the value within a tag
+	Value   func(t time.Time) string // value for the date, nil for MICRO
+}
+
+// calverMicro is the counter that is incremented for each release on the
+// same date
+const calverMicro string = "MICRO"
+
+// calverTokens is ordered so longer tokens are matched first
+var calverTokens = []*calverToken{
+	{Name: calverMicro, Pattern: `0|[1-9]\d*`},
+	{Name: "YYYY", Pattern: `[1-9]\d{3}`, Value: func(t time.Time) string { return strconv.Itoa(t.Year()) }},
+	{Name: "0Y", Pattern: `\d{2,}`, Value: func(t time.Time) string { return fmt.Sprintf("%02d", t.Year()-2000) }},
+	{Name: "YY", Pattern: `0|[1-9]\d*`, Value: func(t time.Time) string { return strconv.Itoa(t.Year() - 2000) }},
+	{Name: "0M", Pattern: `0[1-9]|1[0-2]`, Value: func(t time.Time) string { return fmt.Sprintf("%02d", t.Month()) }},
+	{Name: "MM", Pattern: `1[0-2]|[1-9]`, Value: func(t time.Time) string { return strconv.Itoa(int(t.Month())) }},
+	{Name: "0W", Pattern: `0[1-9]|[1-4]\d|5[0-3]`, Value: func(t time.Time) string { _, w := t.ISOWeek(); return fmt.Sprintf("%02d", w) }},
+	{Name: "WW", Pattern: `[1-4]\d|5[0-3]|[1-9]`, Value: func(t time.Time) string { _, w := t.ISOWeek(); return strconv.Itoa(w) }},
+	{Name: "0D", Pattern: `0[1-9]|[12]\d|3[01]`, Value: func(t time.Time) string { return fmt.Sprintf("%02d", t.Day()) }},
+	{Name: "DD", Pattern: `[12]\d|3[01]|[1-9]`, Value: func(t time.Time) string { return strconv.Itoa(t.Day()) }},
+}
+
+// calverPart is either a token or a literal separator within the format
+type calverPart struct {
+	Token   *calverToken
+	Literal string
+}
+
+// CalVer is a calendar versioning scheme (https://calver.org) using a
+// format like `YYYY.0M.MICRO` or `YYYY.0M.0D-MICRO`.
+//
+// The format must end with MICRO, which counts the releases made on the
+// same date (starting at 0). Prereleases use the same suffix and build
+// counter as semver (`2026.10.3-branch.1`).
+type CalVer struct {
+	Format string
+	Prefix string           // tags must start with this prefix, empty uses the default single letter prefix
+	Now    func() time.Time // clock used for the date, defaults to time.Now
+
+	parts []*calverPart
+	exp   *regexp.Regexp
+}
+
+// NewCalVer parses the format and returns the scheme. When format is empty
+// DefaultCalVerFormat is used, and when now is nil time.Now is used.
+func NewCalVer(format string, prefix string, now func() time.Time) (calver *CalVer, err error) {
+	var (
+		pattern string
+		dates   int
+	)
+	if format == "" {
+		format = DefaultCalVerFormat
+	}
+	if now == nil {
+		now = time.Now
+	}
+	calver = &CalVer{Format: format, Prefix: prefix, Now: now, parts: []*calverPart{}}
+
+	for rest := format; rest != ""; {
+		var found *calverToken
+		for _, token := range calverTokens {
+			if strings.HasPrefix(rest, token.Name) {
+				found = token
+				break
+			}
+		}
+		if found != nil {
+			calver.parts = append(calver.parts, &calverPart{Token: found})
+			pattern += fmt.Sprintf("(?:%s)", found.Pattern)
+			rest = rest[len(found.Name):]
+			if found.Value != nil {
+				dates++
+			}
+			continue
+		}
+		if !strings.ContainsAny(rest[:1], calverSeparators) {
+			err = fmt.Errorf(ErrInvalidCalVerFormat, format, fmt.Sprintf("unknown token at [%s]", rest))
+			return nil, err
+		}
+		calver.parts = append(calver.parts, &calverPart{Literal: rest[:1]})
+		pattern += regexp.QuoteMeta(rest[:1])
+		rest = rest[1:]
+	}
+
+	last := calver.parts[len(calver.parts)-1]
+	if last.Token == nil || last.Token.Name != calverMicro || strings.Count(format, calverMicro) != 1 {
+		return nil, fmt.Errorf(ErrInvalidCalVerFormat, format, "must end with a single MICRO")
+	}
+	if dates == 0 {
+		return nil, fmt.Errorf(ErrInvalidCalVerFormat, format, "must contain a date")
+	}
+	// replace the trailing micro pattern with a named group so it can be found
+	pattern = strings.TrimSuffix(pattern, fmt.Sprintf("(?:%s)", last.Token.Pattern))
+	calver.exp = regexp.MustCompile(fmt.Sprintf(`%s(?P<date>%s)(?P<micro>%s)%s%s$`,
+		regexPrefixFor(prefix),
+		pattern,
+		last.Token.Pattern,
+		regexPrerelease,
+		regexBuildMetadata,
+	))
+	return
+}
+
+func (self *CalVer) Name() SchemeName {
+	return SCHEME_CALVER
+}
+
+// date returns the date part of the version (everything before MICRO) for
+// the time passed
+func (self *CalVer) date(t time.Time) (date string) {
+	for _, part := range self.parts {
+		if part.Token == nil {
+			date += part.Literal
+		} else if part.Token.Value != nil {
+			date += part.Token.Value(t)
+		}
+	}
+	return
+}
+
+// build creates the version from the date and micro values, with the first
+// two numbers of the date used as the major and minor
+func build(date string, micro string) (s *Semver) {
+	var numbers = regexp.MustCompile(`\d+`).FindAllString(date, -1)
+
+	s = &Semver{Valid: true, Core: date + micro, Major: numbers[0], Minor: "0", Patch: micro}
+	if len(numbers) > 1 {
+		s.Minor = numbers[1]
+	}
+	return
+}
+
+// Parse converts the tag name to a calendar version, returning nil if it
+// does not match the format
+func (self *CalVer) Parse(ref string) (s *Semver) {
+	var matches = self.exp.FindStringSubmatch(ref)
+	if matches == nil {
+		return nil
+	}
+	group := func(name string) string { return matches[self.exp.SubexpIndex(name)] }
+
+	s = build(group("date"), group("micro"))
+	s.Original = ref
+	s.Prefix = group("prefix")
+	s.PreleaseName = group("prerelease")
+	s.BuildMetadata = group("buildmetadata")
+	// if prerelease is set, then split the prerelease and build number up
+	if i := strings.LastIndex(s.PreleaseName, "."); i > 0 {
+		s.PrereleaseBuild = s.PreleaseName[i+1:]
+		s.PreleaseName = s.PreleaseName[:i]
+	}
+	return
+}
+
+// Release returns the next calendar version for the current date, which is
+// the highest existing MICRO for the date + 1, or 0 for the first release
+// of that date.
+//
+// All bumps are treated the same apart from `NONE`, which returns the last
+// release instead (if there is one).
+func (self *CalVer) Release(lg *slog.Logger, existing []*Semver, bump Increment) (next *Semver) {
+	var (
+		releases = SortByPrecedence(GetReleases(existing))
+		date     = self.date(self.Now().UTC())
+		micro    = ""
+		prefix   = ""
+	)
+	lg = lg.With("operation", "CalVer.Release", "bump", string(bump), "date", date)
+
+	if len(releases) > 0 {
+		last := *releases[len(releases)-1]
+		prefix = last.Prefix
+		if bump == NO_BUMP {
+			lg.Debug("no bump, using last release ... ", "last", last.String())
+			return &last
+		}
+	}
+	// find the highest micro already used for this date
+	for _, release := range releases {
+		if release.Core == date+release.Patch && (micro == "" || compareNumeric(release.Patch, micro) > 0) {
+			micro = release.Patch
+		}
+	}
+	if micro == "" {
+		micro = "0"
+	} else {
+		micro = inc(micro)
+	}
+
+	next = build(date, micro)
+	next.Prefix = prefix
+	lg.Debug("next release ... ", "next", next.String())
+	return
+}
+
+// Prerelease uses the next release (see Release) with the suffix and a build
+// counter (2026.10.3-suffix.1)
+func (self *CalVer) Prerelease(lg *slog.Logger, existing []*Semver, bump Increment, suffix string) (next *Semver) {
+	lg = lg.With("operation", "CalVer.Prerelease", "bump", string(bump), "suffix", suffix)
+	next = self.Release(lg, existing, bump)
+	next = prereleaseOf(lg, existing, next, suffix)
+	return
+}
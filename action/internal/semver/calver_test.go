@@ -0,0 +1,135 @@
+package semver
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"opg-github-actions/action/internal/logger"
+)
+
+// fixed clock for the tests - 18th october 2026
+var calverNow = func() time.Time { return time.Date(2026, time.October, 18, 9, 30, 0, 0, time.UTC) }
+
+type calverFormatFixture struct {
+	Format        string
+	ErrorContains string
+}
+
+func TestCalVerNew(t *testing.T) {
+	var tests = []*calverFormatFixture{
+		{Format: ""},
+		{Format: "YYYY.0M.MICRO"},
+		{Format: "YYYY.0M.0D-MICRO"},
+		{Format: "YY.0W.MICRO"},
+		{Format: "YYYY.0M", ErrorContains: "must end with a single MICRO"},
+		{Format: "MICRO.YYYY.MICRO", ErrorContains: "must end with a single MICRO"},
+		{Format: "MICRO", ErrorContains: "must contain a date"},
+		{Format: "YYYY/0M.MICRO", ErrorContains: "unknown token"},
+		{Format: "YYYY.Q.MICRO", ErrorContains: "unknown token"},
+	}
+
+	for i, test := range tests {
+		_, err := NewCalVer(test.Format, "", calverNow)
+		if test.ErrorContains == "" && err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+		}
+		if test.ErrorContains != "" && (err == nil || !strings.Contains(err.Error(), test.ErrorContains)) {
+			t.Errorf("[%d] expected error containing [%s], actual [%v]", i, test.ErrorContains, err)
+		}
+	}
+}
+
+type calverParseFixture struct {
+	Format   string
+	Tag      string
+	Valid    bool
+	Expected *Components
+}
+
+func TestCalVerParse(t *testing.T) {
+	var tests = []*calverParseFixture{
+		{Format: "YYYY.0M.MICRO", Tag: "2026.10.3", Valid: true, Expected: &Components{Major: 2026, Minor: 10, Patch: 3, Release: true}},
+		{Format: "YYYY.0M.MICRO", Tag: "v2026.09.0-feature.2", Valid: true, Expected: &Components{Prefix: "v", Major: 2026, Minor: 9, Patch: 0, Prerelease: "feature.2"}},
+		{Format: "YYYY.0M.0D-MICRO", Tag: "v2026.10.18-1", Valid: true, Expected: &Components{Prefix: "v", Major: 2026, Minor: 10, Patch: 1, Release: true}},
+		{Format: "YYYY.0M.0D-MICRO", Tag: "v2026.10.18-1-branch.3", Valid: true, Expected: &Components{Prefix: "v", Major: 2026, Minor: 10, Patch: 1, Prerelease: "branch.3"}},
+		{Format: "YYYY.0M.MICRO", Tag: "v1.2.3", Valid: false},
+		{Format: "YYYY.0M.MICRO", Tag: "2026.9.3", Valid: false},
+		{Format: "YYYY.MM.MICRO", Tag: "2026.9.3", Valid: true, Expected: &Components{Major: 2026, Minor: 9, Patch: 3, Release: true}},
+	}
+
+	for i, test := range tests {
+		calver, _ := NewCalVer(test.Format, "", calverNow)
+		actual := calver.Parse(test.Tag)
+		if (actual != nil) != test.Valid {
+			t.Errorf("[%d] expected [%s] valid to be [%t]", i, test.Tag, test.Valid)
+			continue
+		}
+		if actual == nil {
+			continue
+		}
+		if *actual.Components() != *test.Expected {
+			t.Errorf("[%d] expected [%+v], actual [%+v]", i, test.Expected, actual.Components())
+		}
+		if actual.String() != test.Tag {
+			t.Errorf("[%d] expected string [%s], actual [%s]", i, test.Tag, actual.String())
+		}
+	}
+}
+
+type calverReleaseFixture struct {
+	Format   string
+	Existing []string
+	Bump     Increment
+	Suffix   string
+	Expected string
+}
+
+func TestCalVerRelease(t *testing.T) {
+	var lg = logger.New("error", "text")
+	var tests = []*calverReleaseFixture{
+		// first release
+		{Format: "YYYY.0M.MICRO", Existing: []string{}, Bump: PATCH, Expected: "2026.10.0"},
+		// micro is incremented within the same month, ignoring older months
+		{Format: "YYYY.0M.MICRO", Existing: []string{"v2026.09.7", "v2026.10.0", "v2026.10.1"}, Bump: MINOR, Expected: "v2026.10.2"},
+		// a new month resets the micro
+		{Format: "YYYY.0M.MICRO", Existing: []string{"v2026.09.7"}, Bump: MAJOR, Expected: "v2026.10.0"},
+		// no bump returns the last release
+		{Format: "YYYY.0M.MICRO", Existing: []string{"v2026.09.7", "v2026.09.10"}, Bump: NO_BUMP, Expected: "v2026.09.10"},
+		// daily releases
+		{Format: "YYYY.0M.0D-MICRO", Existing: []string{"v2026.10.17-4", "v2026.10.18-1"}, Bump: PATCH, Expected: "v2026.10.18-2"},
+		{Format: "YY.0W.MICRO", Existing: []string{}, Bump: PATCH, Expected: "26.42.0"},
+		// prereleases increment their build number
+		{Format: "YYYY.0M.MICRO", Existing: []string{"v2026.10.0", "v2026.10.1-feature.1", "v2026.10.1-feature.2"}, Bump: PATCH, Suffix: "feature", Expected: "v2026.10.1-feature.3"},
+		{Format: "YYYY.0M.MICRO", Existing: []string{"v2026.10.0"}, Bump: PATCH, Suffix: "other", Expected: "v2026.10.1-other.1"},
+	}
+
+	for i, test := range tests {
+		var actual *Semver
+		calver, _ := NewCalVer(test.Format, "", calverNow)
+		existing := []*Semver{}
+		for _, tag := range test.Existing {
+			existing = append(existing, calver.Parse(tag))
+		}
+		if test.Suffix != "" {
+			actual = calver.Prerelease(lg, existing, test.Bump, test.Suffix)
+		} else {
+			actual = calver.Release(lg, existing, test.Bump)
+		}
+		if actual.String() != test.Expected {
+			t.Errorf("[%d] expected [%s], actual [%s]", i, test.Expected, actual.String())
+		}
+	}
+}
+
+func TestCalVerCompare(t *testing.T) {
+	calver, _ := NewCalVer("YYYY.MM.MICRO", "", calverNow)
+	var ordered = []string{"2025.12.4", "2026.9.0", "2026.9.10", "2026.10.0-beta.1", "2026.10.0"}
+
+	for i := 1; i < len(ordered); i++ {
+		a, b := calver.Parse(ordered[i-1]), calver.Parse(ordered[i])
+		if Compare(a, b) >= 0 {
+			t.Errorf("[%d] expected [%s] < [%s]", i, a.String(), b.String())
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package semver
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// compareNumeric compares two strings of digits without converting them to
+// integers, so very large values do not overflow
+func compareNumeric(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+	return strings.Compare(a, b)
+}
+
+// isNumeric checks the string only contains digits
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// prerelease returns the full prerelease string, joining the name and build
+// back together
+func prerelease(s *Semver) (pre string) {
+	pre = s.PreleaseName
+	if s.PrereleaseBuild != "" {
+		pre = pre + "." + s.PrereleaseBuild
+	}
+	return
+}
+
+// comparePrerelease compares two prerelease strings using the semver
+// precedence rules for dot separated identifiers
+func comparePrerelease(a, b string) int {
+	var (
+		aIds = strings.Split(a, ".")
+		bIds = strings.Split(b, ".")
+	)
+	// no prerelease has a higher precedence than a prerelease
+	if a == "" || b == "" {
+		return len(b) - len(a)
+	}
+
+	for i := 0; i < len(aIds) && i < len(bIds); i++ {
+		var (
+			x, y       = aIds[i], bIds[i]
+			xNum, yNum = isNumeric(x), isNumeric(y)
+			c          int
+		)
+		switch {
+		case xNum && yNum:
+			c = compareNumeric(x, y)
+		case xNum:
+			c = -1
+		case yNum:
+			c = 1
+		default:
+			c = strings.Compare(x, y)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return len(aIds) - len(bIds)
+}
+
+// Compare returns an integer comparing the precedence of two semvers - negative
+// when a < b, 0 when a == b and positive when a > b
+//
+// Follows the semver spec (https://semver.org/#spec-item-11), so the prefix
+// and build metadata are ignored.
+//
+// Calendar versions (see CalVer) compare each number in their core in turn.
+func Compare(a, b *Semver) int {
+	if a.Core != "" || b.Core != "" {
+		if c := compareSegments(segments(a), segments(b)); c != 0 {
+			return c
+		}
+		return comparePrerelease(prerelease(a), prerelease(b))
+	}
+	if c := compareNumeric(a.Major, b.Major); c != 0 {
+		return c
+	}
+	if c := compareNumeric(a.Minor, b.Minor); c != 0 {
+		return c
+	}
+	if c := compareNumeric(a.Patch, b.Patch); c != 0 {
+		return c
+	}
+	return comparePrerelease(prerelease(a), prerelease(b))
+}
+
+// segments returns the numbers within the version core
+func segments(s *Semver) []string {
+	if s.Core != "" {
+		return regexp.MustCompile(`\d+`).FindAllString(s.Core, -1)
+	}
+	return []string{s.Major, s.Minor, s.Patch}
+}
+
+// compareSegments compares each numeric segment in turn, with more segments
+// being higher when all others are equal
+func compareSegments(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := compareNumeric(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return len(a) - len(b)
+}
+
+// SortByPrecedence returns a copy of the semvers sorted by their precedence
+// (see Compare), lowest first.
+func SortByPrecedence(versions []*Semver) (sorted []*Semver) {
+	sorted = []*Semver{}
+	for _, v := range versions {
+		if v != nil {
+			sorted = append(sorted, v)
+		}
+	}
+	slices.SortStableFunc(sorted, Compare)
+	return
+}
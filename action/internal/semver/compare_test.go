@@ -0,0 +1,68 @@
+package semver
+
+import "testing"
+
+type tCompare struct {
+	A        string
+	B        string
+	Expected int
+}
+
+func TestSemverCompare(t *testing.T) {
+	var tests = []*tCompare{
+		{A: "1.0.0", B: "1.0.0", Expected: 0},
+		{A: "v1.0.0", B: "1.0.0", Expected: 0},
+		{A: "1.0.0+b1", B: "1.0.0+b2", Expected: 0},
+		{A: "1.0.0", B: "2.0.0", Expected: -1},
+		{A: "2.0.0", B: "2.1.0", Expected: -1},
+		{A: "2.1.0", B: "2.1.1", Expected: -1},
+		{A: "1.10.0", B: "1.9.0", Expected: 1},
+		{A: "1.0.0-alpha", B: "1.0.0", Expected: -1},
+		{A: "1.0.0-alpha", B: "1.0.0-alpha.1", Expected: -1},
+		{A: "1.0.0-alpha.1", B: "1.0.0-alpha.beta", Expected: -1},
+		{A: "1.0.0-alpha.beta", B: "1.0.0-beta", Expected: -1},
+		{A: "1.0.0-beta", B: "1.0.0-beta.2", Expected: -1},
+		{A: "1.0.0-beta.2", B: "1.0.0-beta.11", Expected: -1},
+		{A: "1.0.0-beta.11", B: "1.0.0-rc.1", Expected: -1},
+		{A: "1.0.0-rc.1", B: "1.0.0", Expected: -1},
+		{A: "99999999999999999999999.0.0", B: "9999999999999999999999.0.0", Expected: 1},
+	}
+
+	for _, test := range tests {
+		actual := Compare(FromString(test.A), FromString(test.B))
+		// normalise to -1, 0, 1
+		switch {
+		case actual < 0:
+			actual = -1
+		case actual > 0:
+			actual = 1
+		}
+		if actual != test.Expected {
+			t.Errorf("error comparing [%s] to [%s], expected [%d] actual [%d]", test.A, test.B, test.Expected, actual)
+		}
+	}
+}
+
+func TestSemverSortByPrecedence(t *testing.T) {
+	var (
+		versions = []*Semver{
+			FromString("1.10.0"),
+			FromString("1.0.0"),
+			FromString("1.0.0-rc.1"),
+			nil,
+			FromString("1.9.0"),
+			FromString("1.0.0-beta.11"),
+			FromString("1.0.0-beta.2"),
+		}
+		expected = []string{"1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.9.0", "1.10.0"}
+		sorted   = SortByPrecedence(versions)
+	)
+	if len(sorted) != len(expected) {
+		t.Fatalf("expected [%d] versions, actual [%d]", len(expected), len(sorted))
+	}
+	for i, v := range sorted {
+		if v.String() != expected[i] {
+			t.Errorf("[%d] expected [%s] actual [%s]", i, expected[i], v.String())
+		}
+	}
+}
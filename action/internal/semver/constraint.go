@@ -0,0 +1,291 @@
+package semver
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const ErrInvalidConstraint string = "error: invalid version constraint [%s]"
+
+type Operator string
+
+const (
+	OP_EQUAL         Operator = "="
+	OP_NOT_EQUAL     Operator = "!="
+	OP_GREATER       Operator = ">"
+	OP_GREATER_EQUAL Operator = ">="
+	OP_LESS          Operator = "<"
+	OP_LESS_EQUAL    Operator = "<="
+	OP_PESSIMISTIC   Operator = "~>"
+	OP_CARET         Operator = "^" // npm style, only used in ranges (see ParseRange)
+	OP_TILDE         Operator = "~" // npm style, only used in ranges (see ParseRange)
+)
+
+// operators allowed in terraform style constraints and npm style ranges; the
+// longer operators come first so `~>` is not matched as `~`
+const (
+	regexConstraintOperators string = `=|!=|>=|<=|>|<|~>`
+	regexRangeOperators      string = `=|!=|>=|<=|>|<|~>|~|\^`
+)
+
+// regexConstraint matches a single constraint with the operators (%s); the
+// version may be partial (`1`, `1.2`) and the operator is optional
+const regexConstraint string = `^\s*(?P<operator>%s)?\s*v?(?P<major>0|[1-9]\d*)(?:\.(?P<minor>0|[1-9]\d*))?(?:\.(?P<patch>0|[1-9]\d*))?` +
+	`(?:-(?P<prerelease>[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?(?:\+(?P<buildmetadata>[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?\s*$`
+
+// Constraint is a single operator and version pair, like `>= 1.2`
+type Constraint struct {
+	Operator Operator
+	Version  *Semver
+	Segments int // number of version segments set in the constraint (`1.2` => 2)
+}
+
+// String returns the constraint in its normalised form
+func (self *Constraint) String() string {
+	return fmt.Sprintf("%s %s", self.Operator, self.Version.Stringy(false))
+}
+
+// Check tests the semver against this constraint.
+//
+// Versions with a prerelease only ever match when the constraint also has a
+// prerelease for the same major.minor.patch, matching terraforms handling
+// of prereleases.
+func (self *Constraint) Check(v *Semver) (ok bool) {
+	return Constraints{self}.match(v, false)
+}
+
+// allows returns true when the constraint has a prerelease of the same
+// major.minor.patch as the semver, which prerelease versions need to match
+func (self *Constraint) allows(v *Semver) bool {
+	return self.Version.IsPrerelease() && compareCore(v, self.Version) == 0
+}
+
+// within compares the semver against the constraint without the prerelease
+// restrictions of Check
+func (self *Constraint) within(v *Semver) (ok bool) {
+	var c = Compare(v, self.Version)
+
+	switch self.Operator {
+	case OP_EQUAL:
+		ok = c == 0
+	case OP_NOT_EQUAL:
+		ok = c != 0
+	case OP_GREATER:
+		ok = c > 0
+	case OP_GREATER_EQUAL:
+		ok = c >= 0
+	case OP_LESS:
+		ok = c < 0
+	case OP_LESS_EQUAL:
+		ok = c <= 0
+	case OP_PESSIMISTIC:
+		// must be at least the constraint version, and all but the last
+		// segment set in the constraint must match (`~> 1.2` => `>= 1.2, < 2.0`).
+		// Like terraform, a single segment has no upper bound (`~> 1` => `>= 1`)
+		ok = c >= 0
+		if ok && self.Segments > 1 {
+			ok = compareNumeric(v.Major, self.Version.Major) == 0
+		}
+		if ok && self.Segments > 2 {
+			ok = compareNumeric(v.Minor, self.Version.Minor) == 0
+		}
+	case OP_CARET:
+		// must be at least the constraint version, and the first non-zero
+		// segment set cannot change (`^1.2` => `>= 1.2, < 2.0`, `^0.2.3` =>
+		// `>= 0.2.3, < 0.3.0`)
+		ok = c >= 0 && compareNumeric(v.Major, self.Version.Major) == 0
+		if ok && self.Version.Major == "0" && self.Segments > 1 {
+			ok = compareNumeric(v.Minor, self.Version.Minor) == 0
+			if ok && self.Version.Minor == "0" && self.Segments > 2 {
+				ok = compareNumeric(v.Patch, self.Version.Patch) == 0
+			}
+		}
+	case OP_TILDE:
+		// must be at least the constraint version, and only patch changes are
+		// allowed when the minor is set (`~1.2.3` => `>= 1.2.3, < 1.3.0`,
+		// `~1` => `>= 1.0.0, < 2.0.0`)
+		ok = c >= 0 && compareNumeric(v.Major, self.Version.Major) == 0
+		if ok && self.Segments > 1 {
+			ok = compareNumeric(v.Minor, self.Version.Minor) == 0
+		}
+	}
+	return
+}
+
+// compareCore compares only the major, minor & patch segments
+func compareCore(a, b *Semver) int {
+	return Compare(
+		&Semver{Major: a.Major, Minor: a.Minor, Patch: a.Patch},
+		&Semver{Major: b.Major, Minor: b.Minor, Patch: b.Patch},
+	)
+}
+
+// Constraints is a set of constraints that must all be met
+type Constraints []*Constraint
+
+// String returns all constraints as a comma separated string
+func (self Constraints) String() string {
+	var all = []string{}
+	for _, c := range self {
+		all = append(all, c.String())
+	}
+	return strings.Join(all, ", ")
+}
+
+// Check tests the semver against all the constraints
+func (self Constraints) Check(v *Semver) bool {
+	return self.match(v, false)
+}
+
+// match checks all constraints are met. A prerelease version needs every
+// constraint to have a prerelease of the same major.minor.patch, or only one
+// of them when anyPrerelease is set (the npm style used by Range).
+func (self Constraints) match(v *Semver, anyPrerelease bool) bool {
+	if v == nil {
+		return false
+	}
+	var allowed = v.IsRelease() || !anyPrerelease
+
+	for _, c := range self {
+		if !c.within(v) {
+			return false
+		}
+		if c.allows(v) {
+			allowed = true
+		} else if v.IsPrerelease() && !anyPrerelease {
+			return false
+		}
+	}
+	return allowed
+}
+
+// Matching returns the versions that meet all the constraints, sorted by
+// precedence with the lowest first
+func (self Constraints) Matching(versions []*Semver) (matching []*Semver) {
+	matching = []*Semver{}
+	for _, v := range SortByPrecedence(versions) {
+		if self.Check(v) {
+			matching = append(matching, v)
+		}
+	}
+	return
+}
+
+// Exact returns the version when the constraints pin a single full version
+// (`1.6.5` or `= 1.6.5`), otherwise nil
+func (self Constraints) Exact() (exact *Semver) {
+	if len(self) == 1 && self[0].Operator == OP_EQUAL && self[0].Segments == 3 {
+		exact = self[0].Version
+	}
+	return
+}
+
+// Versions returns the versions referenced by the constraints, skipping
+// any that are duplicated
+func (self Constraints) Versions() (versions []*Semver) {
+	var seen = map[string]bool{}
+	versions = []*Semver{}
+	for _, c := range self {
+		var key = c.Version.Stringy(false)
+		if !seen[key] {
+			seen[key] = true
+			versions = append(versions, c.Version)
+		}
+	}
+	return
+}
+
+// Candidates returns the versions referenced by all the constraints along
+// with the next patch version of each, which gives a set of versions that
+// will find an overlap between constraints like `> 1.5` and `< 1.6` without
+// needing a list of real versions.
+func Candidates(all ...Constraints) (candidates []*Semver) {
+	var seen = map[string]bool{}
+	candidates = []*Semver{}
+
+	for _, constraints := range all {
+		for _, v := range constraints.Versions() {
+			var next = &Semver{Valid: true, Major: v.Major, Minor: v.Minor, Patch: inc(v.Patch)}
+			for _, c := range []*Semver{v, next} {
+				var key = c.Stringy(false)
+				if !seen[key] {
+					seen[key] = true
+					candidates = append(candidates, c)
+				}
+			}
+		}
+	}
+	return
+}
+
+// ParseConstraint converts a single constraint string (`~> 1.2`) into a
+// Constraint. When there is no operator, `=` is used.
+func ParseConstraint(s string) (constraint *Constraint, err error) {
+	return parseConstraint(s, regexConstraintOperators)
+}
+
+// parseConstraint converts the constraint string, only allowing the operators
+// passed
+func parseConstraint(s string, operators string) (constraint *Constraint, err error) {
+	var (
+		exp     = regexp.MustCompile(fmt.Sprintf(regexConstraint, operators))
+		matches = exp.FindStringSubmatch(s)
+		values  = map[string]string{}
+	)
+	if len(matches) == 0 {
+		err = fmt.Errorf(ErrInvalidConstraint, s)
+		return
+	}
+	for i, name := range exp.SubexpNames() {
+		values[name] = matches[i]
+	}
+
+	constraint = &Constraint{
+		Operator: Operator(values["operator"]),
+		Segments: 1,
+		Version: &Semver{
+			Original:      strings.TrimSpace(s),
+			Valid:         true,
+			Major:         values["major"],
+			Minor:         "0",
+			Patch:         "0",
+			BuildMetadata: values["buildmetadata"],
+		},
+	}
+	if constraint.Operator == "" {
+		constraint.Operator = OP_EQUAL
+	}
+	if values["minor"] != "" {
+		constraint.Version.Minor = values["minor"]
+		constraint.Segments = 2
+	}
+	if values["patch"] != "" {
+		constraint.Version.Patch = values["patch"]
+		constraint.Segments = 3
+	}
+	// split the prerelease in the same way as parse does
+	if pre := values["prerelease"]; pre != "" {
+		constraint.Version.PreleaseName = pre
+		if i := strings.LastIndex(pre, "."); i > 0 {
+			constraint.Version.PreleaseName = pre[:i]
+			constraint.Version.PrereleaseBuild = pre[i+1:]
+		}
+	}
+	return
+}
+
+// ParseConstraints converts a comma separated set of constraints, in the
+// style used by terraform (`>= 1.3, < 2.0`), into Constraints
+func ParseConstraints(s string) (constraints Constraints, err error) {
+	constraints = Constraints{}
+
+	for _, part := range strings.Split(s, ",") {
+		var c *Constraint
+		if c, err = ParseConstraint(part); err != nil {
+			return
+		}
+		constraints = append(constraints, c)
+	}
+	return
+}
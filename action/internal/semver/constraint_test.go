@@ -0,0 +1,148 @@
+package semver
+
+import (
+	"strings"
+	"testing"
+)
+
+type tConstraintCheck struct {
+	Constraint string
+	Version    string
+	Expected   bool
+}
+
+func TestSemverConstraintsCheck(t *testing.T) {
+	var tests = []*tConstraintCheck{
+		{Constraint: "1.6.5", Version: "1.6.5", Expected: true},
+		{Constraint: "1.6.5", Version: "1.6.6", Expected: false},
+		{Constraint: "= 1.6", Version: "1.6.0", Expected: true},
+		{Constraint: "!= 1.6.0", Version: "1.6.0", Expected: false},
+		{Constraint: "!= 1.6.0", Version: "1.6.1", Expected: true},
+		{Constraint: ">= 1.1.0", Version: "1.1.0", Expected: true},
+		{Constraint: ">= 1.1.0", Version: "1.0.9", Expected: false},
+		{Constraint: "> 1.1.0", Version: "1.1.0", Expected: false},
+		{Constraint: "< 2.0", Version: "1.99.99", Expected: true},
+		{Constraint: "<= 2.0", Version: "2.0.0", Expected: true},
+		{Constraint: ">= 1.3, < 2.0", Version: "1.14.3", Expected: true},
+		{Constraint: ">= 1.3, < 2.0", Version: "2.0.0", Expected: false},
+		{Constraint: ">=1.3,<2.0", Version: "1.2.9", Expected: false},
+		// pessimistic constraints
+		{Constraint: "~> 1.5.0", Version: "1.5.7", Expected: true},
+		{Constraint: "~> 1.5.0", Version: "1.6.0", Expected: false},
+		{Constraint: "~> 1.5.2", Version: "1.5.1", Expected: false},
+		{Constraint: "~> 1.5", Version: "1.9.0", Expected: true},
+		{Constraint: "~> 1.5", Version: "1.4.0", Expected: false},
+		{Constraint: "~> 1.5", Version: "2.0.0", Expected: false},
+		{Constraint: "~> 1", Version: "1.9.0", Expected: true},
+		{Constraint: "~> 1", Version: "2.0.0", Expected: true},
+		{Constraint: "~> 1", Version: "0.9.0", Expected: false},
+		// prereleases only match exact prerelease constraints
+		{Constraint: ">= 1.5.0", Version: "1.6.0-beta1", Expected: false},
+		{Constraint: "~> 1.5", Version: "1.6.0-rc1", Expected: false},
+		{Constraint: "1.6.0-beta1", Version: "1.6.0-beta1", Expected: true},
+		{Constraint: ">= 1.6.0-beta1", Version: "1.6.0-beta2", Expected: true},
+		{Constraint: ">= 1.6.0-beta1", Version: "1.7.0-beta2", Expected: false},
+		{Constraint: ">= 1.6.0-beta1", Version: "1.6.0", Expected: true},
+	}
+
+	for _, test := range tests {
+		constraints, err := ParseConstraints(test.Constraint)
+		if err != nil {
+			t.Errorf("unexpected error parsing [%s]: %s", test.Constraint, err.Error())
+			continue
+		}
+		actual := constraints.Check(FromString(test.Version))
+		if actual != test.Expected {
+			t.Errorf("error checking [%s] against [%s], expected [%t] actual [%t]", test.Version, test.Constraint, test.Expected, actual)
+		}
+	}
+}
+
+func TestSemverConstraintsInvalid(t *testing.T) {
+	var tests = []string{
+		"",
+		">= ",
+		"~ 1.2",
+		"=> 1.2",
+		"1.2.3.4",
+		">= 1.2, ",
+		"latest",
+	}
+
+	for _, test := range tests {
+		if _, err := ParseConstraints(test); err == nil {
+			t.Errorf("expected an error parsing [%s]", test)
+		}
+	}
+}
+
+type tConstraintMatching struct {
+	Constraint string
+	Versions   []string
+	Expected   []string
+}
+
+func TestSemverConstraintsMatching(t *testing.T) {
+	var tests = []*tConstraintMatching{
+		{
+			Constraint: "~> 1.5.0",
+			Versions:   []string{"1.4.0", "1.5.0", "1.5.10", "1.5.2", "1.6.0", "1.5.11-rc1"},
+			Expected:   []string{"1.5.0", "1.5.2", "1.5.10"},
+		},
+		{
+			Constraint: ">= 1.3, < 2.0",
+			Versions:   []string{"2.0.0", "1.14.3", "1.3.0", "1.2.9", "2.0.0-alpha1"},
+			Expected:   []string{"1.3.0", "1.14.3"},
+		},
+		{
+			Constraint: "1.6.5",
+			Versions:   []string{"1.6.4", "1.6.6"},
+			Expected:   []string{},
+		},
+	}
+
+	for i, test := range tests {
+		constraints, _ := ParseConstraints(test.Constraint)
+		versions, _ := FromStrings(test.Versions...)
+		actual := constraints.Matching(versions)
+		if len(actual) != len(test.Expected) {
+			t.Errorf("[%d] expected [%v] actual [%v]", i, test.Expected, Strings(actual, true))
+			continue
+		}
+		for idx, v := range actual {
+			if v.String() != test.Expected[idx] {
+				t.Errorf("[%d:%d] expected [%s] actual [%s]", i, idx, test.Expected[idx], v.String())
+			}
+		}
+	}
+}
+
+type tConstraintCandidates struct {
+	Constraints []string
+	Expected    []string
+}
+
+func TestSemverConstraintsCandidates(t *testing.T) {
+	var tests = []*tConstraintCandidates{
+		{
+			Constraints: []string{"> 1.5", "< 1.6"},
+			Expected:    []string{"1.5.0", "1.5.1", "1.6.0", "1.6.1"},
+		},
+		{
+			Constraints: []string{">= 1.3, < 2.0", "~> 1.3.0"},
+			Expected:    []string{"1.3.0", "1.3.1", "2.0.0", "2.0.1"},
+		},
+	}
+
+	for i, test := range tests {
+		var all = []Constraints{}
+		for _, str := range test.Constraints {
+			c, _ := ParseConstraints(str)
+			all = append(all, c)
+		}
+		actual := Strings(Candidates(all...), false)
+		if strings.Join(actual, ",") != strings.Join(test.Expected, ",") {
+			t.Errorf("[%d] expected [%v] actual [%v]", i, test.Expected, actual)
+		}
+	}
+}
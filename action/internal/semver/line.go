@@ -0,0 +1,100 @@
+package semver
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// DefaultLinePattern matches maintenance branches like `release/1.x` and
+// `release/1.4.x`
+const DefaultLinePattern string = `^release/(?P<major>0|[1-9]\d*)(?:\.(?P<minor>0|[1-9]\d*))?\.x$`
+
+const (
+	ErrInvalidLinePattern string = "error: release line pattern [%s] must be a valid regex with a named (?P<major>) group: %s"
+	ErrBumpNotAllowed     string = "error: a [%s] bump is not allowed on release line [%s]"
+	ErrNoReleaseInLine    string = "error: no existing releases found for release line [%s]"
+)
+
+// Line is a release line that versions are restricted to, so maintenance
+// branches can release patches of an older version.
+//
+// A major line (`1.x`) allows patch and, when AllowMinor is set, minor
+// bumps. A minor line (`1.4.x`) only allows patch bumps. Major bumps are
+// never allowed as they would leave the line.
+type Line struct {
+	Major      string
+	Minor      string // empty for a major line
+	AllowMinor bool
+}
+
+// String returns the line in the `1.x` / `1.4.x` format
+func (self *Line) String() string {
+	if self.Minor != "" {
+		return fmt.Sprintf("%s.%s.x", self.Major, self.Minor)
+	}
+	return fmt.Sprintf("%s.x", self.Major)
+}
+
+// Contains checks if the semver is within the line
+func (self *Line) Contains(s *Semver) bool {
+	if compareNumeric(s.Major, self.Major) != 0 {
+		return false
+	}
+	return self.Minor == "" || compareNumeric(s.Minor, self.Minor) == 0
+}
+
+// Filter returns the semvers within the line
+func (self *Line) Filter(existing []*Semver) (within []*Semver) {
+	within = []*Semver{}
+	for _, s := range existing {
+		if s != nil && self.Contains(s) {
+			within = append(within, s)
+		}
+	}
+	return
+}
+
+// Allows returns an error when the bump would leave the line
+func (self *Line) Allows(bump Increment) (err error) {
+	var allowed = []Increment{NO_BUMP, PATCH}
+
+	if self.Minor == "" && self.AllowMinor {
+		allowed = append(allowed, MINOR)
+	}
+	if !slices.Contains(allowed, bump) {
+		err = fmt.Errorf(ErrBumpNotAllowed, bump, self.String())
+	}
+	return
+}
+
+// LineFromBranch uses the pattern to find the release line for the branch.
+//
+// The pattern must be a regex with a named `major` group and can optionally
+// have a named `minor` group (see DefaultLinePattern). When the pattern is
+// empty or does not match the branch, line is nil.
+func LineFromBranch(pattern string, branch string, allowMinor bool) (line *Line, err error) {
+	var exp *regexp.Regexp
+
+	if pattern == "" {
+		return
+	}
+	if exp, err = regexp.Compile(pattern); err != nil {
+		err = fmt.Errorf(ErrInvalidLinePattern, pattern, err.Error())
+		return
+	}
+	if exp.SubexpIndex("major") < 0 {
+		err = fmt.Errorf(ErrInvalidLinePattern, pattern, "missing major group")
+		return
+	}
+
+	m := exp.FindStringSubmatch(branch)
+	if m == nil || m[exp.SubexpIndex("major")] == "" {
+		return
+	}
+	line = &Line{Major: m[exp.SubexpIndex("major")], AllowMinor: allowMinor}
+	if i := exp.SubexpIndex("minor"); i >= 0 {
+		line.Minor = m[i]
+	}
+	return
+}
@@ -0,0 +1,103 @@
+package semver
+
+import (
+	"strings"
+	"testing"
+
+	"opg-github-actions/action/internal/logger"
+)
+
+type lineFixture struct {
+	Pattern       string
+	Branch        string
+	AllowMinor    bool
+	Expected      string // line as a string, empty when nil
+	ErrorContains string
+}
+
+func TestSemverLineFromBranch(t *testing.T) {
+	var tests = []*lineFixture{
+		{Pattern: DefaultLinePattern, Branch: "release/1.x", Expected: "1.x"},
+		{Pattern: DefaultLinePattern, Branch: "release/1.4.x", Expected: "1.4.x"},
+		{Pattern: DefaultLinePattern, Branch: "release/v1.x", Expected: ""},
+		{Pattern: DefaultLinePattern, Branch: "main", Expected: ""},
+		{Pattern: DefaultLinePattern, Branch: "feature/release/1.x", Expected: ""},
+		{Pattern: `^maint-(?P<major>\d+)$`, Branch: "maint-3", Expected: "3.x"},
+		{Pattern: "", Branch: "release/1.x", Expected: ""},
+		{Pattern: `^release/(\d+)$`, Branch: "release/1", ErrorContains: "missing major group"},
+		{Pattern: `^release/(?P<major>\d+$`, Branch: "release/1", ErrorContains: "must be a valid regex"},
+	}
+
+	for i, test := range tests {
+		line, err := LineFromBranch(test.Pattern, test.Branch, test.AllowMinor)
+		if test.ErrorContains != "" {
+			if err == nil || !strings.Contains(err.Error(), test.ErrorContains) {
+				t.Errorf("[%d] expected error containing [%s], actual [%v]", i, test.ErrorContains, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+		}
+		actual := ""
+		if line != nil {
+			actual = line.String()
+		}
+		if actual != test.Expected {
+			t.Errorf("[%d] expected line [%s], actual [%s]", i, test.Expected, actual)
+		}
+	}
+}
+
+type lineBumpFixture struct {
+	Line    *Line
+	Bump    Increment
+	Allowed bool
+}
+
+func TestSemverLineAllows(t *testing.T) {
+	var tests = []*lineBumpFixture{
+		{Line: &Line{Major: "1"}, Bump: PATCH, Allowed: true},
+		{Line: &Line{Major: "1"}, Bump: NO_BUMP, Allowed: true},
+		{Line: &Line{Major: "1"}, Bump: MINOR, Allowed: false},
+		{Line: &Line{Major: "1", AllowMinor: true}, Bump: MINOR, Allowed: true},
+		{Line: &Line{Major: "1", AllowMinor: true}, Bump: MAJOR, Allowed: false},
+		{Line: &Line{Major: "1", Minor: "4", AllowMinor: true}, Bump: MINOR, Allowed: false},
+		{Line: &Line{Major: "1", Minor: "4"}, Bump: PATCH, Allowed: true},
+	}
+
+	for i, test := range tests {
+		err := test.Line.Allows(test.Bump)
+		if (err == nil) != test.Allowed {
+			t.Errorf("[%d] expected [%s] on [%s] allowed to be [%t], actual error [%v]", i, test.Bump, test.Line.String(), test.Allowed, err)
+		}
+	}
+}
+
+type lineReleaseFixture struct {
+	Line     *Line
+	Bump     Increment
+	Existing []string
+	Expected string
+}
+
+// TestSemverLineRelease checks that using the filtered semvers produces the
+// next version within the line rather than the globally highest
+func TestSemverLineRelease(t *testing.T) {
+	var lg = logger.New("error", "text")
+	var existing = []string{"v1.3.0", "v1.4.1", "v1.4.2", "v2.0.0", "v2.1.0"}
+	var tests = []*lineReleaseFixture{
+		{Line: &Line{Major: "1"}, Bump: PATCH, Existing: existing, Expected: "1.4.3"},
+		{Line: &Line{Major: "1", AllowMinor: true}, Bump: MINOR, Existing: existing, Expected: "1.5.0"},
+		{Line: &Line{Major: "1", Minor: "3"}, Bump: PATCH, Existing: existing, Expected: "1.3.1"},
+		{Line: &Line{Major: "2"}, Bump: PATCH, Existing: existing, Expected: "2.1.1"},
+	}
+
+	for i, test := range tests {
+		semvers, _ := FromStrings(test.Existing...)
+		actual := Release(lg, test.Line.Filter(semvers), test.Bump)
+		if actual.Stringy(false) != test.Expected {
+			t.Errorf("[%d] expected [%s], actual [%s]", i, test.Expected, actual.Stringy(false))
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package semver
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const ErrInvalidVersion string = "error: [%s] is not a valid semver"
+
+// regexHyphenRange matches an npm style hyphen range (`1.2.3 - 2.3`)
+const regexHyphenRange string = `^\s*(\S+)\s+-\s+(\S+)\s*$`
+
+// regexOperatorSpace matches the whitespace after an operator so it can be
+// removed before splitting a set of constraints on whitespace
+const regexOperatorSpace string = `(~>|>=|<=|!=|=|>|<|\^|~)\s+`
+
+// Range is a set of Constraints where any one must be met, in the npm style
+// of `^1.2 || >= 2.1 < 3`.
+//
+// Within a set a prerelease version only matches when one of the constraints
+// has a prerelease of the same major.minor.patch, so `>= 1.2.3-beta.1 < 1.3`
+// matches `1.2.3-beta.2`, but not `1.2.4-beta.1`.
+type Range []Constraints
+
+// String returns all sets as a ` || ` separated string
+func (self Range) String() string {
+	var all = []string{}
+	for _, set := range self {
+		all = append(all, set.String())
+	}
+	return strings.Join(all, " || ")
+}
+
+// Check tests the semver against each set of constraints, returning true
+// when any set is met
+func (self Range) Check(v *Semver) bool {
+	if v == nil {
+		return false
+	}
+	for _, set := range self {
+		if set.match(v, true) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matching returns the versions within the range, sorted by precedence with
+// the lowest first
+func (self Range) Matching(versions []*Semver) (matching []*Semver) {
+	matching = []*Semver{}
+	for _, v := range SortByPrecedence(versions) {
+		if self.Check(v) {
+			matching = append(matching, v)
+		}
+	}
+	return
+}
+
+// Highest returns the version with the highest precedence within the range,
+// or nil when none match
+func (self Range) Highest(versions []*Semver) (highest *Semver) {
+	if matching := self.Matching(versions); len(matching) > 0 {
+		highest = matching[len(matching)-1]
+	}
+	return
+}
+
+// next returns the version after a partial constraint version, so `1.2`
+// gives `1.3.0` and `1` gives `2.0.0`
+func (self *Constraint) next() *Semver {
+	var v = &Semver{Valid: true, Major: self.Version.Major, Minor: "0", Patch: "0"}
+	switch self.Segments {
+	case 1:
+		v.Major = inc(v.Major)
+	case 2:
+		v.Minor = inc(self.Version.Minor)
+	}
+	return v
+}
+
+// expandPartial converts a partial version (`1.2`) into the npm style
+// X-range it covers, so `1.2` => `>= 1.2.0, < 1.3.0`, `<= 1.2` => `< 1.3.0`
+// and `> 1.2` => `>= 1.3.0`. Other operators already treat the missing
+// segments as 0 or use the segments themselves.
+func expandPartial(c *Constraint) (set Constraints) {
+	set = Constraints{c}
+	if c.Segments == 3 || c.Version.IsPrerelease() {
+		return
+	}
+	switch c.Operator {
+	case OP_EQUAL:
+		set = Constraints{
+			{Operator: OP_GREATER_EQUAL, Version: c.Version, Segments: c.Segments},
+			{Operator: OP_LESS, Version: c.next(), Segments: 3},
+		}
+	case OP_LESS_EQUAL:
+		set = Constraints{{Operator: OP_LESS, Version: c.next(), Segments: 3}}
+	case OP_GREATER:
+		set = Constraints{{Operator: OP_GREATER_EQUAL, Version: c.next(), Segments: 3}}
+	}
+	return
+}
+
+// parseHyphenRange converts `1.2.3 - 2.3` into `>= 1.2.3, < 2.4.0`; a partial
+// upper version allows anything within it, otherwise it is inclusive
+func parseHyphenRange(s string, lower string, upper string) (set Constraints, err error) {
+	var from, to *Constraint
+
+	if from, err = parseConstraint(lower, regexConstraintOperators); err != nil {
+		return
+	}
+	if to, err = parseConstraint(upper, regexConstraintOperators); err != nil {
+		return
+	}
+	if from.Operator != OP_EQUAL || to.Operator != OP_EQUAL || strings.ContainsAny(lower+upper, "=") {
+		err = fmt.Errorf(ErrInvalidConstraint, s)
+		return
+	}
+	from.Operator = OP_GREATER_EQUAL
+	to.Operator = OP_LESS_EQUAL
+	set = Constraints{from}
+	set = append(set, expandPartial(to)...)
+	return
+}
+
+// parseSet converts a single set of a range, which is either a hyphen range
+// or constraints separated by whitespace or commas. `*` matches any release.
+// Partial versions are expanded to the range they cover (see expandPartial).
+func parseSet(s string) (set Constraints, err error) {
+	var (
+		hyphen = regexp.MustCompile(regexHyphenRange).FindStringSubmatch(s)
+		spaced = regexp.MustCompile(regexOperatorSpace).ReplaceAllString(s, "$1")
+		parts  = strings.FieldsFunc(spaced, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+	)
+	set = Constraints{}
+
+	if hyphen != nil {
+		return parseHyphenRange(s, hyphen[1], hyphen[2])
+	}
+	if strings.TrimSpace(s) == "*" {
+		s = ">= 0.0.0"
+		parts = []string{s}
+	}
+	if len(parts) == 0 || strings.HasSuffix(strings.TrimSpace(s), ",") {
+		err = fmt.Errorf(ErrInvalidConstraint, s)
+		return
+	}
+	for _, part := range parts {
+		var c *Constraint
+		if c, err = parseConstraint(part, regexRangeOperators); err != nil {
+			return
+		}
+		set = append(set, expandPartial(c)...)
+	}
+	return
+}
+
+// ParseRange converts a version range into a Range, supporting:
+//
+//   - comparison operators (`>= 1.3 < 2`, `>= 1.3, < 2.0`)
+//   - npm style caret and tilde (`^1.2`, `~1.2.3`)
+//   - terraform style pessimistic constraints (`~> 1.2`)
+//   - hyphen ranges (`1.2.3 - 2.3`)
+//   - sets joined with `||` where any one must be met (`^1.2 || ^2`)
+//
+// Partial versions cover every version within them, in the style of npm
+// X-ranges, so `1.2` => `>= 1.2.0 < 1.3.0`, `<= 1.2` => `< 1.3.0` and
+// `> 1.2` => `>= 1.3.0`, while full versions without an operator must match
+// exactly (`1.2.3` => `= 1.2.3`).
+func ParseRange(s string) (r Range, err error) {
+	r = Range{}
+
+	for _, part := range strings.Split(s, "||") {
+		var set Constraints
+		if set, err = parseSet(part); err != nil {
+			return
+		}
+		r = append(r, set)
+	}
+	return
+}
+
+// Satisfies checks if the version is within the range (see ParseRange)
+func Satisfies(version string, constraint string) (ok bool, err error) {
+	return SatisfiesWithScheme(&Semantic{}, version, constraint)
+}
+
+// SatisfiesWithScheme works like Satisfies, but uses the scheme to parse the
+// version, so tags with a custom prefix (`release-1.2.3`) can be checked
+func SatisfiesWithScheme(scheme Scheme, version string, constraint string) (ok bool, err error) {
+	var (
+		v = scheme.Parse(version)
+		r Range
+	)
+	if v == nil {
+		err = fmt.Errorf(ErrInvalidVersion, version)
+		return
+	}
+	if r, err = ParseRange(constraint); err != nil {
+		return
+	}
+	ok = r.Check(v)
+	return
+}
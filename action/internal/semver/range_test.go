@@ -0,0 +1,138 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestSemverRangeCheck(t *testing.T) {
+	var tests = []*tConstraintCheck{
+		// comparison operators separated by whitespace or commas
+		{Constraint: ">=1.3 <2", Version: "1.4.0", Expected: true},
+		{Constraint: ">= 1.3 < 2", Version: "2.0.0", Expected: false},
+		{Constraint: ">= 1.3, < 2.0", Version: "1.14.3", Expected: true},
+		{Constraint: "*", Version: "0.0.1", Expected: true},
+		// partial versions cover every version within them
+		{Constraint: "1.2", Version: "1.2.0", Expected: true},
+		{Constraint: "1.2", Version: "1.2.5", Expected: true},
+		{Constraint: "1.2", Version: "1.3.0", Expected: false},
+		{Constraint: "= 1", Version: "1.9.9", Expected: true},
+		{Constraint: "1.2.3", Version: "1.2.4", Expected: false},
+		{Constraint: "<= 1.2", Version: "1.2.5", Expected: true},
+		{Constraint: "<= 1.2", Version: "1.3.0", Expected: false},
+		{Constraint: "< 1.2", Version: "1.2.0", Expected: false},
+		{Constraint: "< 1.2", Version: "1.1.9", Expected: true},
+		{Constraint: "> 1.2", Version: "1.2.5", Expected: false},
+		{Constraint: "> 1.2", Version: "1.3.0", Expected: true},
+		{Constraint: "> 1", Version: "1.9.0", Expected: false},
+		{Constraint: ">= 1.2", Version: "1.2.0", Expected: true},
+		{Constraint: ">= 1.2", Version: "1.1.9", Expected: false},
+		{Constraint: "1.2 - 1.4", Version: "1.4.9", Expected: true},
+		// caret
+		{Constraint: "^1.2", Version: "1.9.9", Expected: true},
+		{Constraint: "^1.2", Version: "1.1.9", Expected: false},
+		{Constraint: "^1.2.3", Version: "2.0.0", Expected: false},
+		{Constraint: "^0.2.3", Version: "0.2.9", Expected: true},
+		{Constraint: "^0.2.3", Version: "0.3.0", Expected: false},
+		{Constraint: "^0.0.3", Version: "0.0.4", Expected: false},
+		{Constraint: "^0.0", Version: "0.0.9", Expected: true},
+		{Constraint: "^0.0", Version: "0.1.0", Expected: false},
+		{Constraint: "^1", Version: "1.99.0", Expected: true},
+		// tilde
+		{Constraint: "~1.2.3", Version: "1.2.9", Expected: true},
+		{Constraint: "~1.2.3", Version: "1.3.0", Expected: false},
+		{Constraint: "~ 1.2", Version: "1.2.0", Expected: true},
+		{Constraint: "~1", Version: "1.9.0", Expected: true},
+		{Constraint: "~1", Version: "2.0.0", Expected: false},
+		// pessimistic constraints still work
+		{Constraint: "~> 1.5", Version: "1.9.0", Expected: true},
+		{Constraint: "~>1.5.0", Version: "1.6.0", Expected: false},
+		// hyphen ranges
+		{Constraint: "1.2.3 - 2.3.4", Version: "2.3.4", Expected: true},
+		{Constraint: "1.2.3 - 2.3.4", Version: "2.3.5", Expected: false},
+		{Constraint: "1.2.3 - 2.3", Version: "2.3.9", Expected: true},
+		{Constraint: "1.2.3 - 2.3", Version: "2.4.0", Expected: false},
+		{Constraint: "1.2 - 2", Version: "2.9.0", Expected: true},
+		{Constraint: "1.2 - 2", Version: "1.1.0", Expected: false},
+		// or
+		{Constraint: "^1.2 || ^3", Version: "3.1.0", Expected: true},
+		{Constraint: "^1.2 || ^3", Version: "2.1.0", Expected: false},
+		{Constraint: "<1 || >=2.0.0 <2.1", Version: "2.0.5", Expected: true},
+		// prereleases need a prerelease of the same version in the set
+		{Constraint: ">=1.2.3-beta.1 <1.3", Version: "1.2.3-beta.2", Expected: true},
+		{Constraint: ">=1.2.3-beta.1 <1.3", Version: "1.2.4-beta.1", Expected: false},
+		{Constraint: "^1.2", Version: "1.3.0-rc.1", Expected: false},
+		{Constraint: "^1.2 || 1.3.0-rc.1", Version: "1.3.0-rc.1", Expected: true},
+	}
+
+	for i, test := range tests {
+		actual, err := Satisfies(test.Version, test.Constraint)
+		if err != nil {
+			t.Errorf("[%d] unexpected error checking [%s]: %s", i, test.Constraint, err.Error())
+			continue
+		}
+		if actual != test.Expected {
+			t.Errorf("[%d] error checking [%s] against [%s], expected [%t] actual [%t]", i, test.Version, test.Constraint, test.Expected, actual)
+		}
+	}
+}
+
+func TestSemverRangeWithScheme(t *testing.T) {
+	var tests = []*tConstraintCheck{
+		{Constraint: "^1.2", Version: "release-1.4.0", Expected: true},
+		{Constraint: "<= 1.2", Version: "release-1.2.3", Expected: true},
+		{Constraint: "> 1.2", Version: "release-1.2.3", Expected: false},
+	}
+
+	for i, test := range tests {
+		actual, err := SatisfiesWithScheme(&Semantic{Prefix: "release-"}, test.Version, test.Constraint)
+		if err != nil {
+			t.Errorf("[%d] unexpected error checking [%s]: %s", i, test.Constraint, err.Error())
+			continue
+		}
+		if actual != test.Expected {
+			t.Errorf("[%d] error checking [%s] against [%s], expected [%t] actual [%t]", i, test.Version, test.Constraint, test.Expected, actual)
+		}
+	}
+	if _, err := Satisfies("release-1.2.3", "^1"); err == nil {
+		t.Errorf("expected an error for a custom prefix without a scheme")
+	}
+}
+
+func TestSemverRangeInvalid(t *testing.T) {
+	var tests = []string{
+		"",
+		"^",
+		"^1.2 ||",
+		"1.2.3 - ",
+		"1.2.3 - >2",
+		">= 1.2,",
+		"latest",
+		"=> 1.2",
+	}
+
+	for _, test := range tests {
+		if _, err := ParseRange(test); err == nil {
+			t.Errorf("expected an error parsing [%s]", test)
+		}
+	}
+	if _, err := Satisfies("not-a-version", "^1"); err == nil {
+		t.Errorf("expected an error for an invalid version")
+	}
+}
+
+func TestSemverRangeHighest(t *testing.T) {
+	var tests = []*tConstraintMatching{
+		{Constraint: "^1.2", Versions: []string{"v1.1.0", "v1.2.0", "v1.10.1", "v1.9.0", "v2.0.0", "v1.11.0-rc.1"}, Expected: []string{"v1.10.1"}},
+		{Constraint: "~1.2 || ~1.4", Versions: []string{"v1.2.5", "v1.3.9", "v1.4.1"}, Expected: []string{"v1.4.1"}},
+		{Constraint: ">= 3", Versions: []string{"v1.2.5", "v2.0.0"}, Expected: []string{}},
+	}
+
+	for i, test := range tests {
+		r, _ := ParseRange(test.Constraint)
+		versions, _ := FromStrings(test.Versions...)
+		actual := Strings([]*Semver{r.Highest(versions)}, true)
+		if len(actual) != len(test.Expected) || (len(actual) > 0 && actual[0] != test.Expected[0]) {
+			t.Errorf("[%d] expected [%v] actual [%v]", i, test.Expected, actual)
+		}
+	}
+}
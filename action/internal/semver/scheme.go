@@ -0,0 +1,99 @@
+package semver
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+const (
+	ErrUnknownScheme string = "error: unknown versioning scheme [%s], expected one of %v"
+	ErrInvalidPrefix string = "error: tag prefix [%s] is not valid; it can only contain letters, numbers, `.`, `_`, `/` and `-` and cannot end with a number"
+)
+
+type SchemeName string
+
+const (
+	SCHEME_SEMVER SchemeName = "semver"
+	SCHEME_CALVER SchemeName = "calver"
+)
+
+// Schemes is the list of supported versioning schemes
+var Schemes = []SchemeName{SCHEME_SEMVER, SCHEME_CALVER}
+
+// Scheme is a versioning scheme that can parse existing tags and generate
+// the next release or prerelease from them.
+//
+// Versions from every scheme are represented as a *Semver so they share the
+// same tag discovery, comparison and formatting.
+type Scheme interface {
+	// Name returns the name of the scheme
+	Name() SchemeName
+	// Parse converts the tag name to a version, returning nil when it is
+	// not valid for this scheme
+	Parse(ref string) (s *Semver)
+	// Release returns the next release from the existing versions
+	Release(lg *slog.Logger, existing []*Semver, bump Increment) (next *Semver)
+	// Prerelease returns the next prerelease with the suffix from the
+	// existing versions
+	Prerelease(lg *slog.Logger, existing []*Semver, bump Increment, suffix string) (next *Semver)
+}
+
+// Semantic is the default semver scheme (https://semver.org)
+type Semantic struct {
+	Prefix string // tags must start with this prefix, empty uses the default single letter prefix
+}
+
+func (self *Semantic) Name() SchemeName {
+	return SCHEME_SEMVER
+}
+
+func (self *Semantic) Parse(ref string) (s *Semver) {
+	return FromStringWithPrefix(ref, self.Prefix)
+}
+
+func (self *Semantic) Release(lg *slog.Logger, existing []*Semver, bump Increment) (next *Semver) {
+	return Release(lg, existing, bump)
+}
+
+func (self *Semantic) Prerelease(lg *slog.Logger, existing []*Semver, bump Increment, suffix string) (next *Semver) {
+	return Prerelease(lg, existing, bump, suffix)
+}
+
+// NewScheme returns the scheme for the name, only matching tags that start
+// with the prefix when it is set. The format and clock are only used by
+// calendar versions; when now is nil, time.Now is used.
+func NewScheme(name SchemeName, prefix string, format string, now func() time.Time) (scheme Scheme, err error) {
+	if !ValidPrefix(prefix) {
+		err = fmt.Errorf(ErrInvalidPrefix, prefix)
+		return
+	}
+	switch name {
+	case SCHEME_SEMVER, "":
+		scheme = &Semantic{Prefix: prefix}
+	case SCHEME_CALVER:
+		var calver *CalVer
+		if calver, err = NewCalVer(format, prefix, now); err == nil {
+			scheme = calver
+		}
+	default:
+		err = fmt.Errorf(ErrUnknownScheme, name, Schemes)
+	}
+	return
+}
+
+// FromGitRefsWithScheme works like FromGitRefs, but uses the scheme to parse
+// each git tag / reference
+func FromGitRefsWithScheme(scheme Scheme, refs []*plumbing.Reference) (versions []*Semver, err error) {
+	versions = []*Semver{}
+
+	for _, ref := range refs {
+		if sv := scheme.Parse(ref.Name().Short()); sv != nil {
+			sv.GitRef = ref
+			versions = append(versions, sv)
+		}
+	}
+	return
+}
@@ -49,6 +49,8 @@ const (
 	regexPatch         string = `(?P<patch>0|[1-9]\d*)`
 	regexPrerelease    string = `(?:-(?P<prerelease>(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?`
 	regexBuildMetadata string = `(?:\+(?P<buildmetadata>[0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?`
+	// custom prefixes use characters that are safe in git tags and cannot end in a digit
+	regexValidPrefix string = `^[A-Za-z0-9][A-Za-z0-9._/-]*[A-Za-z._/-]$|^[A-Za-z]$`
 )
 
 type Semver struct {
@@ -62,6 +64,31 @@ type Semver struct {
 	PreleaseName    string              `json:"prerelease"`
 	PrereleaseBuild string              `json:"prereleasebuild"`
 	BuildMetadata   string              `json:"buildmetadata"`
+	Core            string              `json:"core"` // version part for schemes not using major.minor.patch (see CalVer)
+}
+
+// Components is the semver broken into typed parts, used for json output
+type Components struct {
+	Prefix        string `json:"prefix"`
+	Major         int    `json:"major"`
+	Minor         int    `json:"minor"`
+	Patch         int    `json:"patch"`
+	Prerelease    string `json:"prerelease"`     // full prerelease value (`beta.1`)
+	BuildMetadata string `json:"build_metadata"` // build metadata without the `+`
+	Release       bool   `json:"release"`        // true when there is no prerelease
+}
+
+// Components returns the typed parts of the semver
+func (self *Semver) Components() *Components {
+	return &Components{
+		Prefix:        self.Prefix,
+		Major:         atoi(self.Major),
+		Minor:         atoi(self.Minor),
+		Patch:         atoi(self.Patch),
+		Prerelease:    prerelease(self),
+		BuildMetadata: self.BuildMetadata,
+		Release:       self.IsRelease(),
+	}
 }
 
 func (self *Semver) IsPrerelease() bool {
@@ -89,6 +116,18 @@ func (self *Semver) Stringy(includePrefix bool) string {
 
 }
 
+// StringyWithoutMetadata works like Stringy, but the build metadata is not
+// included - used for the git tag as build metadata is not part of the
+// version precedence
+func (self *Semver) StringyWithoutMetadata(includePrefix bool) string {
+	return format(self, &strOpts{
+		PrereleaseName:  true,
+		PrereleaseBuild: true,
+		BuildMetadata:   false,
+		Prefix:          includePrefix,
+	})
+}
+
 type strOpts struct {
 	Prefix          bool
 	PrereleaseName  bool
@@ -111,6 +150,9 @@ func format(s *Semver, opts *strOpts) (str string) {
 		return ""
 	}
 	version = fmt.Sprintf("%s.%s.%s", s.Major, s.Minor, s.Patch)
+	if s.Core != "" {
+		version = s.Core
+	}
 
 	if opts.Prefix {
 		prefix = s.Prefix
@@ -167,13 +209,38 @@ func Equal(a, b *Semver) (equal bool) {
 	if a.BuildMetadata != b.BuildMetadata {
 		return false
 	}
+	if a.Core != b.Core {
+		return false
+	}
 	return
 }
 
+// regexPrefixFor returns the pattern for the prefix; when set only that exact
+// prefix matches, otherwise the default single letter (regexPrefix) is used
+func regexPrefixFor(prefix string) string {
+	if prefix == "" {
+		return regexPrefix
+	}
+	return fmt.Sprintf(`^(?P<prefix>%s)`, regexp.QuoteMeta(prefix))
+}
+
+// ValidPrefix checks the custom prefix can be used in a git tag and will not
+// be confused with the version (so cannot end with a digit)
+func ValidPrefix(prefix string) bool {
+	return prefix == "" || regexp.MustCompile(regexValidPrefix).MatchString(prefix)
+}
+
 // Regex returns the constructed regex pattern to use for semvar parsing
 func Regex() string {
+	return RegexWithPrefix("")
+}
+
+// RegexWithPrefix returns the constructed regex pattern to use for semver
+// parsing where the tags must start with the prefix (`release-`, `chart/`).
+// An empty prefix uses the default single letter prefix.
+func RegexWithPrefix(prefix string) string {
 	return fmt.Sprintf(`(?m)%s%s\.%s\.%s%s%s$`,
-		regexPrefix,
+		regexPrefixFor(prefix),
 		regexMajor,
 		regexMinor,
 		regexPatch,
@@ -252,12 +319,11 @@ func Sort(lg *slog.Logger, versions []*Semver, order SortOrder, prefixes bool) (
 
 // parse takes the semver and runs the regex against its original form to determine
 // the consituient parts
-func parse(s *Semver) (err error) {
+func parse(s *Semver, exp *regexp.Regexp) (err error) {
 	var (
 		asMap   map[string]string = map[string]string{}
 		matches []string          = []string{}
 		str     string            = s.Original
-		exp     *regexp.Regexp    = regexp.MustCompile(Regex())
 	)
 
 	matches = exp.FindStringSubmatch(str)
@@ -285,16 +351,23 @@ func parse(s *Semver) (err error) {
 // If the ref name is invalid or the tag does not parse correctly
 // then nil is returned
 func FromString(ref string) (s *Semver) {
+	return FromStringWithPrefix(ref, "")
+}
+
+// FromStringWithPrefix works like FromString, but the ref must start with
+// the prefix. When prefix is empty the default single letter prefix is used.
+func FromStringWithPrefix(ref string, prefix string) (s *Semver) {
+	var exp = regexp.MustCompile(RegexWithPrefix(prefix))
 	s = &Semver{
 		Original: ref,
 		Valid:    true,
 	}
 
-	if !Valid(s.Original) {
+	if !exp.MatchString(s.Original) {
 		return nil
 	}
 
-	if err := parse(s); err != nil {
+	if err := parse(s, exp); err != nil {
 		return nil
 	}
 
@@ -382,20 +455,77 @@ func atoi(s string) (i int) {
 }
 
 // GetLastRelease returns the last release it can find, or nil
+//
+// Releases are ordered by their precedence (see Compare), so build metadata
+// is ignored.
 func GetLastRelease(lg *slog.Logger, existing []*Semver) (last *Semver) {
 	var releases []*Semver
 
 	lg = lg.With("operation", "GetLastRelease")
 	lg.Debug("sorting releases ... ")
-	// get releases only and sort them descending order so the lastest is on the top
-	releases = Sort(lg, GetReleases(existing), SORT_DESC, false)
+	// get releases only and sort them so the latest is at the end
+	releases = SortByPrecedence(GetReleases(existing))
 	if len(releases) > 0 {
-		last = releases[0]
+		last = releases[len(releases)-1]
 	}
 
 	return
 }
 
+// Find returns the existing semver with the same precedence as the version,
+// ignoring the prefix and build metadata, or nil when there is none.
+//
+// Used to check if a tag for the version already exists, so `v1.2.3+build.1`
+// is found for `v1.2.3`. As Release and Prerelease update the existing
+// semver they use, the version itself is skipped.
+func Find(existing []*Semver, version *Semver) (found *Semver) {
+	for _, exists := range existing {
+		if exists != nil && exists != version && Compare(exists, version) == 0 {
+			return exists
+		}
+	}
+	return
+}
+
+// SafeBuildMetadata converts the value into valid build metadata (dot
+// separated identifiers of `[0-9A-Za-z-]`), replacing any other characters
+// with `-` and removing empty identifiers
+func SafeBuildMetadata(value string) (metadata string) {
+	var (
+		invalid     = regexp.MustCompile(`[^0-9A-Za-z-]+`)
+		identifiers = []string{}
+	)
+	for _, id := range strings.Split(value, ".") {
+		if id = strings.Trim(invalid.ReplaceAllString(id, "-"), "-"); id != "" {
+			identifiers = append(identifiers, id)
+		}
+	}
+	metadata = strings.Join(identifiers, ".")
+	return
+}
+
+// IsLatest checks if the version is a release and that no existing release
+// has a higher precedence (see Compare), so it should be marked as the
+// latest release.
+//
+// Hotfixing an older line (`v1.9.3` when `v2.0.0` exists) is not latest.
+func IsLatest(lg *slog.Logger, existing []*Semver, version *Semver) (latest bool) {
+	lg = lg.With("operation", "IsLatest", "version", version.String())
+
+	if !version.IsRelease() {
+		lg.Debug("prereleases are never latest ... ")
+		return
+	}
+	for _, release := range GetReleases(existing) {
+		if Compare(release, version) > 0 {
+			lg.Debug("higher release found ... ", "release", release.String())
+			return
+		}
+	}
+	latest = true
+	return
+}
+
 // Release runs over the existing Semvers, finds that largest (naturally sorted) version
 // and increments that value by bump.
 //
@@ -439,6 +569,17 @@ func Release(lg *slog.Logger, existing []*Semver, bump Increment) (next *Semver)
 // If gets all prereleases from the existing set and matches those with the same
 // `MAJOR.MINOR.PATCH-suffix.buildNumber` pattern, then increments the buildNumber
 func Prerelease(lg *slog.Logger, existing []*Semver, bump Increment, suffix string) (next *Semver) {
+	lg = lg.With("operation", "Prerelease", "bump", string(bump), "suffix", suffix)
+	// get the release this will be a prerelease of
+	next = Release(lg, existing, bump)
+	next = prereleaseOf(lg, existing, next, suffix)
+	return
+}
+
+// prereleaseOf uses the release as the base line for a prerelease with the
+// suffix, incrementing the build number of any existing prerelease with the
+// same `MAJOR.MINOR.PATCH-suffix` signature
+func prereleaseOf(lg *slog.Logger, existing []*Semver, release *Semver, suffix string) (next *Semver) {
 	var (
 		partial string
 		build   int      = 0
@@ -449,9 +590,7 @@ func Prerelease(lg *slog.Logger, existing []*Semver, bump Increment, suffix stri
 			BuildMetadata:   false,
 		}
 	)
-	lg = lg.With("operation", "Prerelease", "bump", string(bump), "suffix", suffix)
-	// get the last release by passing along -1, so an increment is never triggered
-	next = Release(lg, existing, bump)
+	next = release
 	// now setup the prefixes for this being a prerelease
 	next.PreleaseName = suffix
 	next.PrereleaseBuild = "0"
@@ -610,7 +749,7 @@ func New(ref *plumbing.Reference) (s *Semver) {
 		return nil
 	}
 
-	if err := parse(s); err != nil {
+	if err := parse(s, regexp.MustCompile(Regex())); err != nil {
 		return nil
 	}
 
@@ -362,6 +362,54 @@ func TestSemverFromStringSuccess(t *testing.T) {
 	}
 }
 
+type tFromStrPrefix struct {
+	Ref            string
+	Prefix         string
+	ExpectedPrefix string // empty when the ref should not parse
+}
+
+func TestSemverFromStringWithPrefix(t *testing.T) {
+	var tests = []*tFromStrPrefix{
+		{Ref: "release-1.2.3", Prefix: "release-", ExpectedPrefix: "release-"},
+		{Ref: "terraform-module-0.1.0-beta.1", Prefix: "terraform-module-", ExpectedPrefix: "terraform-module-"},
+		{Ref: "chart/1.0.0", Prefix: "chart/", ExpectedPrefix: "chart/"},
+		{Ref: "v1.2.3", Prefix: "release-", ExpectedPrefix: ""},
+		{Ref: "1.2.3", Prefix: "release-", ExpectedPrefix: ""},
+		{Ref: "other-release-1.2.3", Prefix: "release-", ExpectedPrefix: ""},
+		// prefixes are literal, not patterns
+		{Ref: "chartX1.0.0", Prefix: "chart.", ExpectedPrefix: ""},
+		{Ref: "v1.2.3", Prefix: "", ExpectedPrefix: "v"},
+	}
+
+	for i, test := range tests {
+		actual := FromStringWithPrefix(test.Ref, test.Prefix)
+		if test.ExpectedPrefix == "" {
+			if actual != nil {
+				t.Errorf("[%d] expected [%s] not to parse with prefix [%s]", i, test.Ref, test.Prefix)
+			}
+			continue
+		}
+		if actual == nil {
+			t.Errorf("[%d] expected [%s] to parse with prefix [%s]", i, test.Ref, test.Prefix)
+			continue
+		}
+		if actual.Prefix != test.ExpectedPrefix || actual.String() != test.Ref {
+			t.Errorf("[%d] expected prefix [%s] and [%s], actual [%s] and [%s]", i, test.ExpectedPrefix, test.Ref, actual.Prefix, actual.String())
+		}
+	}
+
+	for _, valid := range []string{"", "v", "release-", "terraform-module-", "chart/", "app.v"} {
+		if !ValidPrefix(valid) {
+			t.Errorf("expected prefix [%s] to be valid", valid)
+		}
+	}
+	for _, invalid := range []string{"release-1", "my prefix", "-", "/chart", "tag~"} {
+		if ValidPrefix(invalid) {
+			t.Errorf("expected prefix [%s] to be invalid", invalid)
+		}
+	}
+}
+
 func TestSemverFromStringFailures(t *testing.T) {
 	var tests = []string{
 		"1",
@@ -517,3 +565,103 @@ func TestSemverValid(t *testing.T) {
 	}
 
 }
+
+func TestSemverComponents(t *testing.T) {
+	var s = FromString("v1.20.3-beta.2+build.5")
+	var c = s.Components()
+
+	if c.Prefix != "v" || c.Major != 1 || c.Minor != 20 || c.Patch != 3 {
+		t.Errorf("expected [v 1 20 3] actual [%s %d %d %d]", c.Prefix, c.Major, c.Minor, c.Patch)
+	}
+	if c.Prerelease != "beta.2" || c.BuildMetadata != "build.5" || c.Release {
+		t.Errorf("expected [beta.2 build.5 false] actual [%s %s %t]", c.Prerelease, c.BuildMetadata, c.Release)
+	}
+}
+
+type latestFixture struct {
+	Version  string
+	Existing []string
+	Expected bool
+}
+
+func TestSemverIsLatest(t *testing.T) {
+	var lg = logger.New("error", "text")
+	var existing = []string{"v1.9.2", "v2.0.0", "v2.1.0-beta.0", "v10.0.0-rc.1"}
+	var tests = []*latestFixture{
+		{Version: "v2.0.1", Existing: existing, Expected: true},
+		{Version: "v2.0.0", Existing: existing, Expected: true},
+		{Version: "2.1.0", Existing: existing, Expected: true},
+		{Version: "v1.9.3", Existing: existing, Expected: false},
+		{Version: "v2.1.0-beta.1", Existing: existing, Expected: false},
+		{Version: "v0.0.1", Existing: []string{}, Expected: true},
+		// build metadata is ignored
+		{Version: "v2.0.0+build.1", Existing: []string{"v2.0.0+build.2"}, Expected: true},
+	}
+
+	for i, test := range tests {
+		semvers, _ := FromStrings(test.Existing...)
+		actual := IsLatest(lg, semvers, FromString(test.Version))
+		if actual != test.Expected {
+			t.Errorf("[%d] expected [%s] latest to be [%t], actual [%t]", i, test.Version, test.Expected, actual)
+		}
+	}
+}
+
+type findFixture struct {
+	Existing []string
+	Version  string
+	Expected string // original of the found semver, empty for none
+}
+
+func TestSemverFind(t *testing.T) {
+	var tests = []*findFixture{
+		{Existing: []string{"v1.2.3+build.1", "v1.2.4"}, Version: "v1.2.3", Expected: "v1.2.3+build.1"},
+		{Existing: []string{"v1.2.3"}, Version: "v1.2.3+abc1234", Expected: "v1.2.3"},
+		{Existing: []string{"1.2.3"}, Version: "v1.2.3", Expected: "1.2.3"},
+		{Existing: []string{"v1.2.3-beta.1"}, Version: "v1.2.3", Expected: ""},
+		{Existing: []string{"v1.2.3-beta.1+build.7"}, Version: "v1.2.3-beta.1", Expected: "v1.2.3-beta.1+build.7"},
+	}
+
+	for i, test := range tests {
+		existing, _ := FromStrings(test.Existing...)
+		found := Find(existing, FromString(test.Version))
+		actual := ""
+		if found != nil {
+			actual = found.Original
+		}
+		if actual != test.Expected {
+			t.Errorf("[%d] expected [%s], actual [%s]", i, test.Expected, actual)
+		}
+	}
+	// the version itself is never found
+	existing, _ := FromStrings("v1.0.0")
+	if found := Find(existing, existing[0]); found != nil {
+		t.Errorf("expected the version itself to be skipped, actual [%s]", found.String())
+	}
+}
+
+func TestSemverBuildMetadata(t *testing.T) {
+	var tests = map[string]string{
+		"abc1234.42":           "abc1234.42",
+		"feature/branch.run 7": "feature-branch.run-7",
+		"..a..b..":             "a.b",
+		"+++":                  "",
+		"2026-10-18":           "2026-10-18",
+	}
+	for value, expected := range tests {
+		if actual := SafeBuildMetadata(value); actual != expected {
+			t.Errorf("expected [%s] to be [%s], actual [%s]", value, expected, actual)
+		}
+	}
+
+	s := FromString("v1.2.3-beta.1+build.7")
+	if actual := s.StringyWithoutMetadata(true); actual != "v1.2.3-beta.1" {
+		t.Errorf("expected [v1.2.3-beta.1], actual [%s]", actual)
+	}
+	// ordering ignores build metadata
+	lg := logger.New("error", "text")
+	existing, _ := FromStrings("v1.9.0+build.20", "v1.10.0+build.1", "v1.10.0-rc.1")
+	if last := GetLastRelease(lg, existing); last.Original != "v1.10.0+build.1" {
+		t.Errorf("expected last release [v1.10.0+build.1], actual [%s]", last.Original)
+	}
+}
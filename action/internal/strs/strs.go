@@ -1,12 +1,25 @@
 package strs
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"regexp"
 	"strings"
 )
 
 const allowedCharacters string = "[^a-zA-Z0-9]+"
 
+// dockerTagCharacters are those not allowed within a docker tag
+// see: https://docs.docker.com/reference/cli/docker/image/tag/
+const dockerTagCharacters string = "[^a-zA-Z0-9_.-]+"
+
+// DockerTagLength is the maximum length of a docker tag
+const DockerTagLength int = 128
+
+// HashLength is the number of characters of the hash suffix used by
+// TruncateWithHash
+const HashLength int = 6
+
 // Clean converts a string to only include alpha numerics characters, so removes
 // forward slashes and so on
 func Clean(s string) (safe string) {
@@ -31,6 +44,40 @@ func Truncate(s string, maxLength int) (short string) {
 	return
 }
 
+// Hash returns a short, stable hex hash of the string that is `length`
+// characters long
+func Hash(s string, length int) (hash string) {
+	var sum = sha256.Sum256([]byte(s))
+
+	hash = Truncate(hex.EncodeToString(sum[:]), length)
+	return
+}
+
+// TruncateWithHash truncates the string to at most maxLength characters, but
+// when the string is too long, the end is replaced with a short hash of the
+// full value so that similar long strings do not collide.
+//
+// `featurepaymentsrefactora` (14) => `featurepae20ff`
+//
+// When maxLength is not longer than the hash, only the hash is returned
+func TruncateWithHash(s string, maxLength int) (short string) {
+	var hash string
+
+	if len(s) <= maxLength {
+		short = s
+		return
+	}
+
+	hash = Hash(s, HashLength)
+	if maxLength <= HashLength {
+		short = Truncate(hash, maxLength)
+		return
+	}
+
+	short = Truncate(s, maxLength-HashLength) + hash
+	return
+}
+
 // Safe cleans the string to only allowed characters and then truncates
 // that result to desired length - returning both values
 func Safe(s string, maxLength int) (safeAndShort string, safe string) {
@@ -39,3 +86,29 @@ func Safe(s string, maxLength int) (safeAndShort string, safe string) {
 	return
 
 }
+
+// SafeWithHash works like Safe, but when the cleaned string needs to be
+// truncated a hash of the full cleaned value replaces the end of the string
+// (see TruncateWithHash)
+func SafeWithHash(s string, maxLength int) (safeAndShort string, safe string) {
+	safe = Clean(s)
+	safeAndShort = TruncateWithHash(safe, maxLength)
+	return
+}
+
+// DockerTag converts the string to match the docker tag grammar
+// (`[a-zA-Z0-9_][a-zA-Z0-9_.-]{0,127}`) by replacing runs of other characters
+// with a hyphen, removing leading periods and hyphens and truncating to
+// DockerTagLength.
+//
+// `feature/my-branch` => `feature-my-branch`
+//
+// `v1.2.3+build.1` => `v1.2.3-build.1`
+func DockerTag(s string) (tag string) {
+	var exp = regexp.MustCompile(dockerTagCharacters)
+
+	tag = exp.ReplaceAllString(Transliterate(s), "-")
+	tag = strings.TrimLeft(tag, ".-")
+	tag = Truncate(tag, DockerTagLength)
+	return
+}
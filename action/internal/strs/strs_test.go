@@ -1,6 +1,9 @@
 package strs
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 type cleanFixture struct {
 	Test     string
@@ -82,3 +85,106 @@ func TestSafeStrings(t *testing.T) {
 	}
 
 }
+
+func TestTruncatedWithHashStrings(t *testing.T) {
+
+	var tests = []*truncateFixture{
+		{Test: "featurepaymentsrefactora", Length: 14, Expected: "featurepae20ff"},
+		{Test: "featurepaymentsrefactorb", Length: 14, Expected: "featurepb9652f"},
+		{Test: "longstringwithslashes", Length: 14, Expected: "longstri4a030b"},
+		{Test: "testbrancha", Length: 14, Expected: "testbrancha"},
+		{Test: "exactlythesame", Length: 14, Expected: "exactlythesame"},
+		{Test: "featurepaymentsrefactora", Length: 4, Expected: "ae20"},
+		{Test: "", Length: 6, Expected: ""},
+	}
+
+	for _, test := range tests {
+		actual := TruncateWithHash(test.Test, test.Length)
+		if actual != test.Expected {
+			t.Errorf("error truncating string with hash [%s], expected [%s] actual [%s]", test.Test, test.Expected, actual)
+		}
+		if len(actual) > test.Length {
+			t.Errorf("error truncating string with hash [%s], length [%d] exceeds max [%d]", test.Test, len(actual), test.Length)
+		}
+	}
+
+}
+
+func TestSafeWithHashStrings(t *testing.T) {
+
+	var tests = []*safeFixture{
+		{Test: "feature/payments-refactor-a", Length: 14, Expected: "featurepae20ff"},
+		{Test: "feature/payments-refactor-b", Length: 14, Expected: "featurepb9652f"},
+		{Test: "no-more-hyphens", Length: 14, Expected: "nomorehyphens"},
+		{Test: "", Length: 6, Expected: ""},
+	}
+
+	for _, test := range tests {
+		actual, _ := SafeWithHash(test.Test, test.Length)
+		if actual != test.Expected {
+			t.Errorf("error creating safe string with hash [%s], expected [%s] actual [%s]", test.Test, test.Expected, actual)
+		}
+	}
+
+}
+
+func TestDockerTagStrings(t *testing.T) {
+
+	var tests = []*cleanFixture{
+		{Test: "v1.2.3", Expected: "v1.2.3"},
+		{Test: "v1.2.3+build.1", Expected: "v1.2.3-build.1"},
+		{Test: "feature/my-branch", Expected: "feature-my-branch"},
+		{Test: "dependabot/go_modules//golang.org/x/text-0.3", Expected: "dependabot-go_modules-golang.org-x-text-0.3"},
+		{Test: "-.leading", Expected: "leading"},
+		{Test: "fix/café", Expected: "fix-cafe"},
+		{Test: "Upper_Case", Expected: "Upper_Case"},
+		{Test: "", Expected: ""},
+	}
+
+	for _, test := range tests {
+		actual := DockerTag(test.Test)
+		if actual != test.Expected {
+			t.Errorf("error creating docker tag [%s], expected [%s] actual [%s]", test.Test, test.Expected, actual)
+		}
+	}
+
+	long := DockerTag(strings.Repeat("a", 200))
+	if len(long) != DockerTagLength {
+		t.Errorf("error creating docker tag, length [%d] exceeds max [%d]", len(long), DockerTagLength)
+	}
+
+}
+
+type transliterateFixture struct {
+	Test     string
+	Expected string
+	Cleaned  string
+}
+
+func TestTransliterateStrings(t *testing.T) {
+
+	var tests = []*transliterateFixture{
+		{Test: "fix/café-menu", Expected: "fix/cafe-menu", Cleaned: "fixcafemenu"},
+		{Test: "straße", Expected: "strasse", Cleaned: "strasse"},
+		{Test: "Ærø-Ørsted", Expected: "Aero-Orsted", Cleaned: "aeroorsted"},
+		{Test: "naïve-façade-señor", Expected: "naive-facade-senor", Cleaned: "naivefacadesenor"},
+		{Test: "Łódź/Kraków", Expected: "Lodz/Krakow", Cleaned: "lodzkrakow"},
+		{Test: "Жёлтый-борщ", Expected: "Zheltyy-borshch", Cleaned: "zheltyyborshch"},
+		{Test: "Ελληνικά", Expected: "Ellinika", Cleaned: "ellinika"},
+		{Test: "ﬁle-①", Expected: "file-1", Cleaned: "file1"},
+		{Test: "plain-ascii/123", Expected: "plain-ascii/123", Cleaned: "plainascii123"},
+		{Test: "emoji-♥-日本", Expected: "emoji-♥-日本", Cleaned: "emoji"},
+		{Test: "", Expected: "", Cleaned: ""},
+	}
+
+	for _, test := range tests {
+		actual := Transliterate(test.Test)
+		if actual != test.Expected {
+			t.Errorf("error transliterating string [%s], expected [%s] actual [%s]", test.Test, test.Expected, actual)
+		}
+		if cleaned := Clean(actual); cleaned != test.Cleaned {
+			t.Errorf("error cleaning transliterated string [%s], expected [%s] actual [%s]", test.Test, test.Cleaned, cleaned)
+		}
+	}
+
+}
@@ -0,0 +1,71 @@
+package strs
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// replacements are characters that do not decompose into an ascii base
+// character and combining marks, so need an explicit ascii version
+var replacements = map[rune]string{
+	// latin
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d", 'þ': "th",
+	'ł': "l", 'ı': "i", 'ħ': "h", 'ŋ': "n", 'ŧ': "t", 'ĸ': "k",
+	// greek
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th",
+	'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p",
+	'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps",
+	'ω': "o",
+	// cyrillic
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'ґ': "g", 'д': "d", 'е': "e", 'є': "ye",
+	'ж': "zh", 'з': "z", 'и': "i", 'і': "i", 'ї': "yi", 'й': "y", 'к': "k", 'л': "l",
+	'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y",
+	'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// replace looks for an ascii replacement for the rune, matching the case of
+// the original where possible
+func replace(r rune) (ascii string, found bool) {
+	var lower = unicode.ToLower(r)
+
+	if ascii, found = replacements[lower]; found && lower != r && ascii != "" {
+		ascii = strings.ToUpper(ascii[:1]) + ascii[1:]
+	}
+	return
+}
+
+// Transliterate converts unicode characters into their closest ascii
+// version (é => e, ß => ss, Ж => Zh) so they are not simply dropped by
+// Clean. Characters without a known ascii version are left as they are.
+//
+// Accented characters are decomposed into their base character and
+// combining marks, with the marks then removed.
+func Transliterate(s string) (ascii string) {
+	var b strings.Builder
+
+	for _, r := range s {
+		if r < unicode.MaxASCII {
+			b.WriteRune(r)
+			continue
+		}
+		if rep, ok := replace(r); ok {
+			b.WriteString(rep)
+			continue
+		}
+		for _, c := range norm.NFKD.String(string(r)) {
+			if unicode.Is(unicode.Mn, c) {
+				continue
+			}
+			if rep, ok := replace(c); ok {
+				b.WriteString(rep)
+			} else {
+				b.WriteRune(c)
+			}
+		}
+	}
+	ascii = b.String()
+	return
+}
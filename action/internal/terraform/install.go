@@ -0,0 +1,222 @@
+package terraform
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"opg-github-actions/action/internal/semver"
+)
+
+// DefaultMirror is the base url of the hashicorp terraform releases
+const DefaultMirror string = "https://releases.hashicorp.com/terraform"
+
+// Errors
+const (
+	ErrInstallVersion  string = "error: [%s] is not an exact terraform version"
+	ErrDownload        string = "error: failed to download [%s]: %s"
+	ErrChecksumMissing string = "error: no checksum found for [%s] in [%s]"
+	ErrChecksum        string = "error: checksum mismatch for [%s]: expected [%s] actual [%s]"
+	ErrBinaryMissing   string = "error: [%s] not found within [%s]"
+)
+
+const (
+	toolCacheEnv string = "RUNNER_TOOL_CACHE" // github actions tool cache location
+	cacheName    string = "opg-github-actions"
+)
+
+type InstallOptions struct {
+	Version        string // exact terraform version to install (`1.14.3`)
+	Mirror         string // base url to download from, matching the layout of DefaultMirror
+	CacheDirectory string // directory to store installed versions in
+	OS             string // operating system of the build (`linux`)
+	Arch           string // architecture of the build (`amd64`)
+}
+
+// Installed is the result of an install
+type Installed struct {
+	Path    string // full path to the terraform binary
+	Version string // version installed
+	Cached  bool   // if the binary was already present in the cache
+}
+
+// DefaultCacheDirectory uses the github actions tool cache when set, falling
+// back to the users cache directory
+func DefaultCacheDirectory() (dir string) {
+	if dir = os.Getenv(toolCacheEnv); dir != "" {
+		return
+	}
+	if d, err := os.UserCacheDir(); err == nil {
+		dir = filepath.Join(d, cacheName)
+	} else {
+		dir = filepath.Join(os.TempDir(), cacheName)
+	}
+	return
+}
+
+// defaultInstallOptions fills in any missing options
+func defaultInstallOptions(options *InstallOptions) *InstallOptions {
+	var o = *options
+	if o.Mirror == "" {
+		o.Mirror = DefaultMirror
+	}
+	if o.CacheDirectory == "" {
+		o.CacheDirectory = DefaultCacheDirectory()
+	}
+	if o.OS == "" {
+		o.OS = runtime.GOOS
+	}
+	if o.Arch == "" {
+		o.Arch = runtime.GOARCH
+	}
+	o.Mirror = strings.TrimSuffix(o.Mirror, "/")
+	o.Version = strings.TrimPrefix(o.Version, "v")
+	return &o
+}
+
+// binaryName returns the name of the terraform binary for the os
+func binaryName(goos string) string {
+	if goos == "windows" {
+		return "terraform.exe"
+	}
+	return "terraform"
+}
+
+// archiveName returns the name of the release zip (`terraform_1.14.3_linux_amd64.zip`)
+func archiveName(o *InstallOptions) string {
+	return fmt.Sprintf("terraform_%s_%s_%s.zip", o.Version, o.OS, o.Arch)
+}
+
+// checksum finds the expected sha256 for the file from the SHA256SUMS content
+func checksum(content []byte, filename string) (sum string) {
+	var scanner = bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) == 2 && fields[1] == filename {
+			sum = strings.ToLower(fields[0])
+			return
+		}
+	}
+	return
+}
+
+// download returns the content of the url, wrapping any error
+func download(url string) (content []byte, err error) {
+	if content, err = fetch(url); err != nil {
+		err = fmt.Errorf(ErrDownload, url, err.Error())
+	}
+	return
+}
+
+// extract writes the named file from the zip content to the destination
+// path, via a temporary file so a partial write is never used. The archive
+// name is only used for errors.
+func extract(content []byte, archive string, name string, destination string) (err error) {
+	var (
+		reader *zip.Reader
+		tmp    *os.File
+		src    io.ReadCloser
+	)
+	if reader, err = zip.NewReader(bytes.NewReader(content), int64(len(content))); err != nil {
+		return
+	}
+	for _, f := range reader.File {
+		if f.Name != name {
+			continue
+		}
+		if err = os.MkdirAll(filepath.Dir(destination), 0755); err != nil {
+			return
+		}
+		if src, err = f.Open(); err != nil {
+			return
+		}
+		defer src.Close()
+		if tmp, err = os.CreateTemp(filepath.Dir(destination), name+".*"); err != nil {
+			return
+		}
+		defer os.Remove(tmp.Name())
+		if _, err = io.Copy(tmp, src); err != nil {
+			tmp.Close()
+			return
+		}
+		if err = tmp.Close(); err != nil {
+			return
+		}
+		if err = os.Chmod(tmp.Name(), 0755); err != nil {
+			return
+		}
+		err = os.Rename(tmp.Name(), destination)
+		return
+	}
+	err = fmt.Errorf(ErrBinaryMissing, name, archive)
+	return
+}
+
+// Install downloads the terraform release zip for the version from the
+// mirror, verifies it against the published SHA256SUMS file and extracts
+// the binary into the cache directory.
+//
+// When the version is already in the cache no downloads are made.
+//
+// Layout of the cache directory:
+//
+//	<cache>/terraform/<version>/<os>_<arch>/terraform
+func Install(lg *slog.Logger, options *InstallOptions) (installed *Installed, err error) {
+	var (
+		sums    []byte
+		archive []byte
+		o       = defaultInstallOptions(options)
+		name    = binaryName(o.OS)
+		zipName = archiveName(o)
+		path    = filepath.Join(o.CacheDirectory, "terraform", o.Version, o.OS+"_"+o.Arch, name)
+	)
+	lg = lg.With("operation", "Install", "version", o.Version, "mirror", o.Mirror)
+	installed = &Installed{Path: path, Version: o.Version}
+
+	if !semver.Valid(o.Version) {
+		err = fmt.Errorf(ErrInstallVersion, options.Version)
+		return
+	}
+	if info, e := os.Stat(path); e == nil && !info.IsDir() {
+		lg.Info("using cached terraform.", "path", path)
+		installed.Cached = true
+		return
+	}
+
+	sumsURL := fmt.Sprintf("%s/%s/terraform_%s_SHA256SUMS", o.Mirror, o.Version, o.Version)
+	zipURL := fmt.Sprintf("%s/%s/%s", o.Mirror, o.Version, zipName)
+
+	lg.Debug("downloading checksums ... ", "url", sumsURL)
+	if sums, err = download(sumsURL); err != nil {
+		return
+	}
+	expected := checksum(sums, zipName)
+	if expected == "" {
+		err = fmt.Errorf(ErrChecksumMissing, zipName, sumsURL)
+		return
+	}
+
+	lg.Info("downloading terraform ... ", "url", zipURL)
+	if archive, err = download(zipURL); err != nil {
+		return
+	}
+	hash := sha256.Sum256(archive)
+	if actual := hex.EncodeToString(hash[:]); actual != expected {
+		err = fmt.Errorf(ErrChecksum, zipName, expected, actual)
+		return
+	}
+
+	if err = extract(archive, zipName, name, path); err != nil {
+		return
+	}
+	lg.Info("installed terraform.", "path", path)
+	return
+}
@@ -0,0 +1,101 @@
+package terraform
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"opg-github-actions/action/internal/logger"
+)
+
+// testMirror creates a stand in for the releases site that serves a zip
+// for the version, along with a SHA256SUMS file using the checksum passed
+// (or the real checksum when empty)
+func testMirror(t *testing.T, version string, sum string, requests *int) *httptest.Server {
+	t.Helper()
+	var (
+		buf     = new(bytes.Buffer)
+		w       = zip.NewWriter(buf)
+		zipName = fmt.Sprintf("terraform_%s_linux_amd64.zip", version)
+	)
+	f, _ := w.Create("terraform")
+	f.Write([]byte("#!/bin/sh\necho " + version))
+	w.Close()
+	if sum == "" {
+		hash := sha256.Sum256(buf.Bytes())
+		sum = hex.EncodeToString(hash[:])
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(fmt.Sprintf("/%s/terraform_%s_SHA256SUMS", version, version), func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		fmt.Fprintf(w, "%s  terraform_%s_darwin_arm64.zip\n%s  %s\n", strings.Repeat("0", 64), version, sum, zipName)
+	})
+	mux.HandleFunc(fmt.Sprintf("/%s/%s", version, zipName), func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		w.Write(buf.Bytes())
+	})
+	return httptest.NewServer(mux)
+}
+
+type installFixture struct {
+	Version       string
+	Checksum      string
+	Request       string
+	ErrorContains string
+}
+
+func TestTerraformInstall(t *testing.T) {
+	var lg = logger.New("error", "text")
+	var tests = []*installFixture{
+		{Version: "1.14.3", Request: "1.14.3"},
+		{Version: "1.14.3", Request: "v1.14.3"},
+		{Version: "1.14.3", Checksum: strings.Repeat("a", 64), Request: "1.14.3", ErrorContains: "checksum mismatch"},
+		{Version: "1.14.3", Request: "1.6.0", ErrorContains: "failed to download"},
+		{Version: "1.14.3", Request: "~> 1.14", ErrorContains: "not an exact terraform version"},
+	}
+
+	for i, test := range tests {
+		var requests = 0
+		var server = testMirror(t, test.Version, test.Checksum, &requests)
+		var options = &InstallOptions{
+			Version:        test.Request,
+			Mirror:         server.URL + "/",
+			CacheDirectory: t.TempDir(),
+			OS:             "linux",
+			Arch:           "amd64",
+		}
+
+		installed, err := Install(lg, options)
+		if test.ErrorContains != "" {
+			if err == nil || !strings.Contains(err.Error(), test.ErrorContains) {
+				t.Errorf("[%d] expected error containing [%s], actual [%v]", i, test.ErrorContains, err)
+			}
+			server.Close()
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+			server.Close()
+			continue
+		}
+		content, _ := os.ReadFile(installed.Path)
+		if !strings.Contains(string(content), test.Version) || installed.Cached {
+			t.Errorf("[%d] expected fresh install of [%s] at [%s]", i, test.Version, installed.Path)
+		}
+		// second install should come from the cache without any requests
+		requests = 0
+		installed, err = Install(lg, options)
+		if err != nil || !installed.Cached || requests != 0 {
+			t.Errorf("[%d] expected cached install without requests, actual cached [%t] requests [%d]", i, installed.Cached, requests)
+		}
+		server.Close()
+	}
+}
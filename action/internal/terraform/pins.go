@@ -0,0 +1,180 @@
+package terraform
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"opg-github-actions/action/internal/semver"
+)
+
+// Errors
+const (
+	ErrInvalidPin     string = "error: %s: [%s] is not a valid terraform version"
+	ErrPinNotFound    string = "error: %s: no terraform version found"
+	ErrPinUnsatisfied string = "error: terraform version [%s] from [%s] does not satisfy required_version [%s]"
+)
+
+const (
+	VersionFile      string = ".terraform-version" // used by tfenv
+	ToolVersionsFile string = ".tool-versions"     // used by asdf
+	toolName         string = "terraform"
+	gitDirectory     string = ".git"
+)
+
+type Source string
+
+const (
+	SOURCE_TERRAFORM_VERSION Source = "terraform-version"
+	SOURCE_TOOL_VERSIONS     Source = "tool-versions"
+	SOURCE_REQUIRED_VERSION  Source = "required_version"
+)
+
+// order the pin files are checked in within each directory
+var pinSources = []Source{SOURCE_TERRAFORM_VERSION, SOURCE_TOOL_VERSIONS}
+
+var pinFiles = map[Source]string{
+	SOURCE_TERRAFORM_VERSION: VersionFile,
+	SOURCE_TOOL_VERSIONS:     ToolVersionsFile,
+}
+
+// Pin is an exact terraform version set in a version file
+type Pin struct {
+	Version  string
+	Source   Source
+	Filename string
+}
+
+// Satisfies checks the pinned version meets the constraint, returning an
+// error naming the file when it does not
+func (self *Pin) Satisfies(constraint string) (err error) {
+	var constraints semver.Constraints
+	if constraint == "" {
+		return
+	}
+	if constraints, err = semver.ParseConstraints(constraint); err != nil {
+		return
+	}
+	if !constraints.Check(semver.FromString(self.Version)) {
+		err = fmt.Errorf(ErrPinUnsatisfied, self.Version, self.Filename, constraint)
+	}
+	return
+}
+
+// lines returns each non empty line with comments removed
+func lines(content []byte) (found []string) {
+	var scanner = bufio.NewScanner(bytes.NewReader(content))
+	found = []string{}
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if line = strings.TrimSpace(line); line != "" {
+			found = append(found, line)
+		}
+	}
+	return
+}
+
+// ParseVersionFile returns the version from a `.terraform-version` file,
+// which is the first line of the file (`1.6.5`)
+func ParseVersionFile(filename string, content []byte) (version string, err error) {
+	var all = lines(content)
+	if len(all) == 0 {
+		err = fmt.Errorf(ErrPinNotFound, filename)
+		return
+	}
+	version = strings.TrimPrefix(all[0], "v")
+	if !semver.Valid(version) {
+		err = fmt.Errorf(ErrInvalidPin, filename, all[0])
+	}
+	return
+}
+
+// ParseToolVersions returns the terraform version from a `.tool-versions`
+// file (`terraform 1.6.5`). When multiple versions are listed the first is
+// used, as asdf does. An empty version means terraform is not listed.
+func ParseToolVersions(filename string, content []byte) (version string, err error) {
+	// lines skips blank lines, so there is always at least one field
+	for _, line := range lines(content) {
+		var fields = strings.Fields(line)
+		if fields[0] != toolName {
+			continue
+		}
+		if len(fields) < 2 {
+			err = fmt.Errorf(ErrPinNotFound, filename)
+			return
+		}
+		version = strings.TrimPrefix(fields[1], "v")
+		if !semver.Valid(version) {
+			err = fmt.Errorf(ErrInvalidPin, filename, fields[1])
+		}
+		return
+	}
+	return
+}
+
+// parsePin reads the version from the file based on its source
+func parsePin(source Source, filename string, content []byte) (string, error) {
+	if source == SOURCE_TOOL_VERSIONS {
+		return ParseToolVersions(filename, content)
+	}
+	return ParseVersionFile(filename, content)
+}
+
+// parents returns the directory and each of its parents, stopping at the
+// root of the git repository (the directory containing `.git`)
+func parents(directory string) (dirs []string) {
+	dirs = []string{}
+	dir, err := filepath.Abs(directory)
+	if err != nil {
+		dir = directory
+	}
+	for {
+		dirs = append(dirs, dir)
+		if _, err := os.Stat(filepath.Join(dir, gitDirectory)); err == nil {
+			return
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return
+		}
+		dir = parent
+	}
+}
+
+// FindPin looks for a pinned terraform version starting in the directory and
+// moving up to the root of the git repository.
+//
+// The nearest directory with a pinned version is used and, within the same
+// directory, a `.terraform-version` file takes precedence over
+// `.tool-versions`. When neither contains a version, nil is returned.
+func FindPin(lg *slog.Logger, directory string) (pin *Pin, err error) {
+	var dirs = parents(directory)
+	lg = lg.With("operation", "FindPin", "directory", directory)
+
+	for _, dir := range dirs {
+		for _, source := range pinSources {
+			var (
+				content  []byte
+				version  string
+				filename = filepath.Join(dir, pinFiles[source])
+			)
+			if content, err = os.ReadFile(filename); err != nil {
+				err = nil
+				continue
+			}
+			if version, err = parsePin(source, filename, content); err != nil {
+				return
+			}
+			if version != "" {
+				lg.Debug("found pinned version.", "file", filename, "version", version)
+				pin = &Pin{Version: version, Source: source, Filename: filename}
+				return
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,164 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"opg-github-actions/action/internal/logger"
+)
+
+type pinFileFixture struct {
+	Content     string
+	Expected    string
+	ShouldError bool
+}
+
+func TestTerraformParseToolVersions(t *testing.T) {
+	var tests = []*pinFileFixture{
+		{Content: "golang 1.25.0\nterraform 1.6.5\n", Expected: "1.6.5"},
+		{Content: "# terraform 1.0.0\nterraform 1.6.5 1.5.0 # comment\n", Expected: "1.6.5"},
+		{Content: "nodejs 20.0.0\n", Expected: ""},
+		{Content: "terraform latest\n", ShouldError: true},
+		{Content: "terraform\n", ShouldError: true},
+	}
+
+	for i, test := range tests {
+		actual, err := ParseToolVersions(ToolVersionsFile, []byte(test.Content))
+		if test.ShouldError && err == nil {
+			t.Errorf("[%d] expected an error, but did not get one", i)
+		} else if !test.ShouldError && err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+		}
+		if actual != test.Expected && !test.ShouldError {
+			t.Errorf("[%d] expected [%s] actual [%s]", i, test.Expected, actual)
+		}
+	}
+}
+
+func TestTerraformParseVersionFile(t *testing.T) {
+	var tests = []*pinFileFixture{
+		{Content: "1.6.5\n", Expected: "1.6.5"},
+		{Content: "\n# pinned\nv1.14.3\n", Expected: "1.14.3"},
+		{Content: "latest:^1.5\n", ShouldError: true},
+		{Content: "\n", ShouldError: true},
+	}
+
+	for i, test := range tests {
+		actual, err := ParseVersionFile(VersionFile, []byte(test.Content))
+		if test.ShouldError && err == nil {
+			t.Errorf("[%d] expected an error, but did not get one", i)
+		} else if !test.ShouldError && err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+		}
+		if actual != test.Expected && !test.ShouldError {
+			t.Errorf("[%d] expected [%s] actual [%s]", i, test.Expected, actual)
+		}
+	}
+}
+
+type findPinFixture struct {
+	Files          map[string]string
+	Directory      string
+	ExpectedSource Source
+	Expected       string
+}
+
+func TestTerraformFindPin(t *testing.T) {
+	var lg = logger.New("error", "text")
+	var tests = []*findPinFixture{
+		{
+			// a nearer .tool-versions beats .terraform-version in a parent
+			Files: map[string]string{
+				".git/HEAD":                     "",
+				VersionFile:                     "1.6.5",
+				"terraform/" + ToolVersionsFile: "terraform 1.5.0",
+			},
+			Directory:      "terraform",
+			ExpectedSource: SOURCE_TOOL_VERSIONS,
+			Expected:       "1.5.0",
+		},
+		{
+			// .terraform-version beats .tool-versions in the same directory
+			Files: map[string]string{
+				".git/HEAD":                     "",
+				ToolVersionsFile:                "terraform 1.4.0",
+				"terraform/" + VersionFile:      "1.6.5",
+				"terraform/" + ToolVersionsFile: "terraform 1.5.0",
+			},
+			Directory:      "terraform",
+			ExpectedSource: SOURCE_TERRAFORM_VERSION,
+			Expected:       "1.6.5",
+		},
+		{
+			// .terraform-version in a parent beats a deeper .tool-versions
+			// that does not list terraform
+			Files: map[string]string{
+				".git/HEAD":                          "",
+				VersionFile:                          "1.6.5",
+				"terraform/" + ToolVersionsFile:      "nodejs 20.0.0",
+				"terraform/prod/" + ToolVersionsFile: "nodejs 20.0.0",
+			},
+			Directory:      "terraform/prod",
+			ExpectedSource: SOURCE_TERRAFORM_VERSION,
+			Expected:       "1.6.5",
+		},
+		{
+			Files: map[string]string{
+				".git/HEAD":                     "",
+				"terraform/" + ToolVersionsFile: "nodejs 20.0.0",
+				ToolVersionsFile:                "terraform 1.5.0",
+			},
+			Directory:      "terraform",
+			ExpectedSource: SOURCE_TOOL_VERSIONS,
+			Expected:       "1.5.0",
+		},
+		{
+			// does not look above the root of the repository
+			Files: map[string]string{
+				VersionFile:           "1.6.5",
+				"repo/.git/HEAD":      "",
+				"repo/terraform/x.tf": "",
+			},
+			Directory: "repo/terraform",
+		},
+	}
+
+	for i, test := range tests {
+		var dir = t.TempDir()
+		for name, content := range test.Files {
+			path := filepath.Join(dir, name)
+			os.MkdirAll(filepath.Dir(path), 0755)
+			os.WriteFile(path, []byte(content), 0644)
+		}
+		pin, err := FindPin(lg, filepath.Join(dir, test.Directory))
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+			continue
+		}
+		if test.Expected == "" {
+			if pin != nil {
+				t.Errorf("[%d] expected no pin, actual [%+v]", i, pin)
+			}
+			continue
+		}
+		if pin == nil || pin.Version != test.Expected || pin.Source != test.ExpectedSource {
+			t.Errorf("[%d] expected [%s:%s] actual [%+v]", i, test.ExpectedSource, test.Expected, pin)
+		}
+	}
+}
+
+func TestTerraformPinSatisfies(t *testing.T) {
+	var pin = &Pin{Version: "1.6.5", Source: SOURCE_TERRAFORM_VERSION, Filename: VersionFile}
+
+	if err := pin.Satisfies("~> 1.6.0"); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if err := pin.Satisfies(""); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if err := pin.Satisfies(">= 1.7"); err == nil || !strings.Contains(err.Error(), VersionFile) {
+		t.Errorf("expected error naming the file, actual [%v]", err)
+	}
+}
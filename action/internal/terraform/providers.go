@@ -0,0 +1,213 @@
+package terraform
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"opg-github-actions/action/internal/semver"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Errors
+const (
+	ErrProviderType string = "error: %s:%d: required_providers entry [%s] must be an object or a string"
+)
+
+const (
+	LockFile                string = ".terraform.lock.hcl"
+	DefaultRegistry         string = "registry.terraform.io"
+	DefaultNamespace        string = "hashicorp"
+	requiredProvidersBlock  string = "required_providers"
+	lockProviderBlock       string = "provider"
+	providerSourceProperty  string = "source"
+	providerVersionProperty string = "version"
+)
+
+// Provider is an entry from `required_providers`, along with the version
+// found in the lock file if there is one
+type Provider struct {
+	Name       string `json:"name"`                // local name of the provider (`aws`)
+	Source     string `json:"source"`              // source address as set (`hashicorp/aws`)
+	Constraint string `json:"version"`             // version constraint (`>= 5.0`)
+	Directory  string `json:"directory,omitempty"` // directory the provider was found in
+	Locked     string `json:"locked,omitempty"`    // version from the lock file
+	Satisfied  *bool  `json:"satisfied,omitempty"` // if the locked version meets the constraint
+}
+
+// Address returns the fully qualified source address as used in the lock
+// file (`registry.terraform.io/hashicorp/aws`)
+func (self *Provider) Address() (address string) {
+	var source = self.Source
+	if source == "" {
+		source = DefaultNamespace + "/" + self.Name
+	}
+	address = strings.ToLower(source)
+	if len(strings.Split(address, "/")) == 2 {
+		address = DefaultRegistry + "/" + address
+	}
+	return
+}
+
+// Lock sets the locked version and checks it against the constraint
+func (self *Provider) Lock(version string) {
+	var (
+		satisfied   = true
+		constraints semver.Constraints
+		err         error
+	)
+	self.Locked = version
+	if self.Constraint != "" {
+		if constraints, err = semver.ParseConstraints(self.Constraint); err != nil {
+			satisfied = false
+		} else {
+			satisfied = constraints.Check(semver.FromString(version))
+		}
+	}
+	self.Satisfied = &satisfied
+}
+
+// objectString returns the string value of the key from an object
+func objectString(val cty.Value, key string) (str string) {
+	if val.Type().HasAttribute(key) {
+		if v := val.GetAttr(key); !v.IsNull() && v.IsKnown() && v.Type() == cty.String {
+			str = v.AsString()
+		}
+	}
+	return
+}
+
+// RequiredProviders parses the terraform file content as hcl and returns
+// every provider set within `required_providers` blocks.
+//
+// Entries can be objects (`aws = { source = "hashicorp/aws", version = "6.25.0" }`)
+// or the legacy string form that only sets the version (`aws = "~> 5.0"`).
+func RequiredProviders(filename string, content []byte) (providers []*Provider, err error) {
+	var body *hclsyntax.Body
+	providers = []*Provider{}
+
+	if body, err = Parse(filename, content); err != nil {
+		return
+	}
+
+	for _, tf := range Blocks(body, terraformBlock) {
+		for _, block := range Blocks(tf.Body, requiredProvidersBlock) {
+			var names = []string{}
+			for name := range block.Body.Attributes {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+
+			for _, name := range names {
+				var (
+					attr     = block.Body.Attributes[name]
+					provider = &Provider{Name: name}
+				)
+				if str, ok := stringValue(attr.Expr); ok {
+					provider.Constraint = str
+				} else if val, diags := attr.Expr.Value(nil); !diags.HasErrors() && (val.Type().IsObjectType() || val.Type().IsMapType()) {
+					provider.Source = objectString(val, providerSourceProperty)
+					provider.Constraint = objectString(val, providerVersionProperty)
+				} else {
+					err = fmt.Errorf(ErrProviderType, filename, attr.SrcRange.Start.Line, name)
+					return
+				}
+				providers = append(providers, provider)
+			}
+		}
+	}
+	return
+}
+
+// LockedVersions parses the lock file content and returns the version of
+// each provider, keyed by its address
+func LockedVersions(filename string, content []byte) (locked map[string]string, err error) {
+	var body *hclsyntax.Body
+	locked = map[string]string{}
+
+	if body, err = Parse(filename, content); err != nil {
+		return
+	}
+	for _, block := range Blocks(body, lockProviderBlock) {
+		if len(block.Labels) == 0 {
+			continue
+		}
+		if attr, ok := block.Body.Attributes[providerVersionProperty]; ok {
+			if version, ok := stringValue(attr.Expr); ok {
+				locked[strings.ToLower(block.Labels[0])] = version
+			}
+		}
+	}
+	return
+}
+
+// Providers reads all `.tf` files within the directory (not recursively) and
+// returns the required providers, sorted by name. Constraints for the same
+// provider in multiple files are combined.
+//
+// When a `.terraform.lock.hcl` exists in the directory, the locked version
+// is added to each provider and checked against the constraint.
+func Providers(lg *slog.Logger, directory string) (providers []*Provider, err error) {
+	var (
+		files  []string
+		locked = map[string]string{}
+		found  = map[string]*Provider{}
+	)
+	lg = lg.With("operation", "Providers", "directory", directory)
+	providers = []*Provider{}
+
+	if files, err = filepath.Glob(filepath.Join(directory, "*"+terraformExtension)); err != nil {
+		return
+	}
+	for _, file := range files {
+		var (
+			content []byte
+			list    []*Provider
+		)
+		if content, err = os.ReadFile(file); err != nil {
+			return
+		}
+		if list, err = RequiredProviders(file, content); err != nil {
+			return
+		}
+		for _, p := range list {
+			existing, ok := found[p.Name]
+			if !ok {
+				found[p.Name] = p
+				providers = append(providers, p)
+				continue
+			}
+			if existing.Source == "" {
+				existing.Source = p.Source
+			}
+			if p.Constraint != "" && existing.Constraint != "" {
+				existing.Constraint = existing.Constraint + ", " + p.Constraint
+			} else if p.Constraint != "" {
+				existing.Constraint = p.Constraint
+			}
+		}
+	}
+
+	lockfile := filepath.Join(directory, LockFile)
+	if content, e := os.ReadFile(lockfile); e == nil {
+		lg.Debug("found lock file.", "file", lockfile)
+		if locked, err = LockedVersions(lockfile, content); err != nil {
+			return
+		}
+	}
+
+	for _, p := range providers {
+		if version, ok := locked[p.Address()]; ok {
+			p.Lock(version)
+		}
+	}
+	slices.SortFunc(providers, func(a, b *Provider) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return
+}
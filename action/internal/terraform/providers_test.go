@@ -0,0 +1,142 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"opg-github-actions/action/internal/logger"
+)
+
+type providersFixture struct {
+	Content       string
+	Expected      []*Provider
+	ErrorContains string
+}
+
+func TestTerraformRequiredProviders(t *testing.T) {
+	var tests = []*providersFixture{
+		{
+			Content: `terraform {
+				required_providers {
+					github = {
+						source  = "integrations/github"
+						version = "6.10.2"
+					}
+					aws = {
+						source  = "hashicorp/aws"
+						version = ">= 6.0"
+					}
+				}
+				required_version = "1.14.3"
+			}`,
+			Expected: []*Provider{
+				{Name: "aws", Source: "hashicorp/aws", Constraint: ">= 6.0"},
+				{Name: "github", Source: "integrations/github", Constraint: "6.10.2"},
+			},
+		},
+		{
+			// legacy string form and object without a version
+			Content: `terraform {
+				required_providers {
+					aws    = "~> 5.0"
+					random = { source = "hashicorp/random" }
+				}
+			}`,
+			Expected: []*Provider{
+				{Name: "aws", Source: "", Constraint: "~> 5.0"},
+				{Name: "random", Source: "hashicorp/random", Constraint: ""},
+			},
+		},
+		{
+			Content: `provider "aws" { region = "eu-west-1" }`,
+		},
+		{
+			Content: `terraform {
+				required_providers {
+					aws = 1
+				}
+			}`,
+			ErrorContains: "versions.tf:3:",
+		},
+	}
+
+	for i, test := range tests {
+		actual, err := RequiredProviders("versions.tf", []byte(test.Content))
+		if test.ErrorContains != "" {
+			if err == nil || !strings.Contains(err.Error(), test.ErrorContains) {
+				t.Errorf("[%d] expected error containing [%s], actual [%v]", i, test.ErrorContains, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+		}
+		if len(actual) != len(test.Expected) {
+			t.Errorf("[%d] expected [%d] providers, actual [%d]", i, len(test.Expected), len(actual))
+			continue
+		}
+		for idx, p := range actual {
+			e := test.Expected[idx]
+			if p.Name != e.Name || p.Source != e.Source || p.Constraint != e.Constraint {
+				t.Errorf("[%d:%d] expected [%+v] actual [%+v]", i, idx, e, p)
+			}
+		}
+	}
+}
+
+func TestTerraformProviders(t *testing.T) {
+	var (
+		lg    = logger.New("error", "text")
+		dir   = t.TempDir()
+		files = map[string]string{
+			"versions.tf": `terraform {
+				required_providers {
+					aws    = { source = "hashicorp/aws", version = ">= 6.0" }
+					github = { source = "integrations/github", version = "~> 6.10.0" }
+					random = "3.6.0"
+				}
+			}`,
+			"modules.tf": `terraform {
+				required_providers {
+					aws = { source = "hashicorp/aws", version = "< 7.0" }
+				}
+			}`,
+			LockFile: `provider "registry.terraform.io/hashicorp/aws" {
+				version     = "6.25.0"
+				constraints = ">= 6.0, < 7.0"
+			}
+			provider "registry.terraform.io/integrations/github" {
+				version = "6.11.0"
+			}`,
+		}
+		expected = map[string][]string{
+			"aws":    {"hashicorp/aws", "< 7.0, >= 6.0", "6.25.0", "true"},
+			"github": {"integrations/github", "~> 6.10.0", "6.11.0", "false"},
+			"random": {"", "3.6.0", "", ""},
+		}
+	)
+	for name, content := range files {
+		os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+	}
+
+	providers, err := Providers(lg, dir)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if len(providers) != len(expected) {
+		t.Errorf("expected [%d] providers, actual [%d]", len(expected), len(providers))
+	}
+	for _, p := range providers {
+		var satisfied = ""
+		if p.Satisfied != nil {
+			satisfied = strconv.FormatBool(*p.Satisfied)
+		}
+		actual := []string{p.Source, p.Constraint, p.Locked, satisfied}
+		if strings.Join(actual, "|") != strings.Join(expected[p.Name], "|") {
+			t.Errorf("[%s] expected [%v] actual [%v]", p.Name, expected[p.Name], actual)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package terraform
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"opg-github-actions/action/internal/semver"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultReleasesIndex is the hashicorp index of all terraform releases
+const DefaultReleasesIndex string = "https://releases.hashicorp.com/terraform/index.json"
+
+const ErrFetchingVersions string = "error: failed to fetch available versions from [%s]: %s"
+
+// releasesIndex is the shape of the hashicorp releases index file, only
+// the version keys are used
+type releasesIndex struct {
+	Name     string                     `json:"name"`
+	Versions map[string]json.RawMessage `json:"versions"`
+}
+
+// isURL checks if the source looks like a http(s) url
+func isURL(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// fetch returns the content of the url
+func fetch(source string) (content []byte, err error) {
+	var (
+		resp   *http.Response
+		client = &http.Client{Timeout: 30 * time.Second}
+	)
+	if resp, err = client.Get(source); err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("unexpected status [%s]", resp.Status)
+		return
+	}
+	content, err = io.ReadAll(resp.Body)
+	return
+}
+
+// parseVersions reads versions from either the releases index json or a
+// plain list with one version per line. Lines that are empty, comments or
+// not valid versions are skipped.
+func parseVersions(content []byte) (versions []*semver.Semver) {
+	var (
+		index   = &releasesIndex{}
+		strs    = []string{}
+		scanner = bufio.NewScanner(bytes.NewReader(content))
+	)
+
+	if err := json.Unmarshal(content, index); err == nil {
+		for v := range index.Versions {
+			strs = append(strs, v)
+		}
+	} else {
+		for scanner.Scan() {
+			strs = append(strs, strings.TrimSpace(scanner.Text()))
+		}
+	}
+
+	versions, _ = semver.FromStrings(strs...)
+	return
+}
+
+// Versions returns all the available terraform versions from the source,
+// which can be either a local file path or a http(s) url.
+//
+// The content can be the hashicorp releases index json (see DefaultReleasesIndex)
+// or a plain list of versions, one per line.
+func Versions(lg *slog.Logger, source string) (versions []*semver.Semver, err error) {
+	var content []byte
+
+	lg = lg.With("operation", "Versions", "source", source)
+	versions = []*semver.Semver{}
+
+	if isURL(source) {
+		lg.Debug("fetching versions from url ... ")
+		content, err = fetch(source)
+	} else {
+		lg.Debug("reading versions from file ... ")
+		content, err = os.ReadFile(source)
+	}
+	if err != nil {
+		err = fmt.Errorf(ErrFetchingVersions, source, err.Error())
+		return
+	}
+
+	versions = parseVersions(content)
+	lg.Debug("found versions ... ", "count", len(versions))
+	return
+}
@@ -0,0 +1,79 @@
+package terraform
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"opg-github-actions/action/internal/logger"
+	"opg-github-actions/action/internal/semver"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+const testIndex string = `{
+  "name": "terraform",
+  "versions": {
+    "1.5.0": {"name": "terraform", "version": "1.5.0"},
+    "1.5.7": {"name": "terraform", "version": "1.5.7"},
+    "1.6.0-beta1": {"name": "terraform", "version": "1.6.0-beta1"},
+    "1.6.0": {"name": "terraform", "version": "1.6.0"}
+  }
+}`
+
+const testList string = `# available versions
+1.5.0
+1.5.7
+
+1.6.0-beta1
+not-a-version
+1.6.0
+`
+
+func TestTerraformVersionsFromSources(t *testing.T) {
+	var (
+		lg       = logger.New("error", "text")
+		dir      = t.TempDir()
+		expected = []string{"1.5.0", "1.5.7", "1.6.0-beta1", "1.6.0"}
+		server   = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/terraform/index.json":
+				w.Write([]byte(testIndex))
+			case "/versions.txt":
+				w.Write([]byte(testList))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	)
+	defer server.Close()
+
+	os.WriteFile(filepath.Join(dir, "index.json"), []byte(testIndex), 0644)
+	os.WriteFile(filepath.Join(dir, "versions.txt"), []byte(testList), 0644)
+
+	sources := []string{
+		server.URL + "/terraform/index.json",
+		server.URL + "/versions.txt",
+		filepath.Join(dir, "index.json"),
+		filepath.Join(dir, "versions.txt"),
+	}
+
+	for _, source := range sources {
+		versions, err := Versions(lg, source)
+		if err != nil {
+			t.Errorf("unexpected error for [%s]: %s", source, err.Error())
+			continue
+		}
+		actual := semver.Strings(semver.SortByPrecedence(versions), true)
+		if !slices.Equal(actual, expected) {
+			t.Errorf("error with versions from [%s], expected [%v] actual [%v]", source, expected, actual)
+		}
+	}
+
+	// missing sources should error
+	for _, source := range []string{server.URL + "/missing.json", filepath.Join(dir, "missing.txt")} {
+		if _, err := Versions(lg, source); err == nil {
+			t.Errorf("expected an error for [%s]", source)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package terraform
+
+import (
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// directories that are never scanned
+var skipDirectories = []string{".terraform", ".git"}
+
+const terraformExtension string = ".tf"
+
+// Root is a directory containing terraform files (a root or a module) and
+// all of the `required_version` values set within it
+type Root struct {
+	Directory string
+	Versions  []*RequiredVersion
+}
+
+// Constraint joins the required versions of this root into a single
+// constraint string
+func (self *Root) Constraint() string {
+	return Constraint(self.Versions)
+}
+
+// Scan walks the directory tree and returns every directory that has a
+// `required_version` set in one of its `.tf` files, sorted by directory.
+//
+// Files without a terraform block or a required_version are skipped, as
+// modules commonly leave these out. The `.terraform` and `.git`
+// directories are not scanned.
+func Scan(lg *slog.Logger, directory string) (roots []*Root, err error) {
+	var found = map[string]*Root{}
+	lg = lg.With("operation", "Scan", "directory", directory)
+	roots = []*Root{}
+
+	err = filepath.WalkDir(directory, func(path string, d fs.DirEntry, e error) (err error) {
+		var (
+			content  []byte
+			body     *hclsyntax.Body
+			versions []*RequiredVersion
+		)
+		if e != nil {
+			return e
+		}
+		if d.IsDir() {
+			if slices.Contains(skipDirectories, d.Name()) {
+				return filepath.SkipDir
+			}
+			return
+		}
+		if filepath.Ext(path) != terraformExtension {
+			return
+		}
+
+		if content, err = os.ReadFile(path); err != nil {
+			return
+		}
+		if body, err = Parse(path, content); err != nil {
+			return
+		}
+		if versions, err = requiredVersions(path, content, Blocks(body, terraformBlock)); err != nil || len(versions) == 0 {
+			return
+		}
+
+		dir := filepath.Dir(path)
+		if _, ok := found[dir]; !ok {
+			found[dir] = &Root{Directory: dir, Versions: []*RequiredVersion{}}
+		}
+		found[dir].Versions = append(found[dir].Versions, versions...)
+		lg.Debug("found required_version.", "file", path, "constraint", Constraint(versions))
+		return
+	})
+	if err != nil {
+		return
+	}
+
+	for _, root := range found {
+		roots = append(roots, root)
+	}
+	slices.SortFunc(roots, func(a, b *Root) int {
+		return strings.Compare(a.Directory, b.Directory)
+	})
+	return
+}
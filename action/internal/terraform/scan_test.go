@@ -0,0 +1,47 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"opg-github-actions/action/internal/logger"
+)
+
+func TestTerraformScan(t *testing.T) {
+	var (
+		lg    = logger.New("error", "text")
+		dir   = t.TempDir()
+		files = map[string]string{
+			"account/versions.tf":             `terraform { required_version = "~> 1.5.0" }`,
+			"account/main.tf":                 `resource "null_resource" "a" {}`,
+			"environment/versions.tf":         `terraform { required_version = ">= 1.3" }`,
+			"environment/backend.tf":          `terraform { required_version = "< 2.0" }`,
+			"modules/network/main.tf":         `terraform {}`,
+			"account/.terraform/modules/x.tf": `terraform { required_version = "0.12.0" }`,
+			"README.md":                       `terraform { required_version = "0.11.0" }`,
+		}
+		expected = map[string]string{
+			filepath.Join(dir, "account"):     "~> 1.5.0",
+			filepath.Join(dir, "environment"): "< 2.0, >= 1.3",
+		}
+	)
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+	}
+
+	roots, err := Scan(lg, dir)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if len(roots) != len(expected) {
+		t.Errorf("expected [%d] roots, actual [%d]", len(expected), len(roots))
+	}
+	for _, root := range roots {
+		if expected[root.Directory] != root.Constraint() {
+			t.Errorf("[%s] expected [%s] actual [%s]", root.Directory, expected[root.Directory], root.Constraint())
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package terraform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Errors
+const (
+	ErrParsingFile            string = "error: failed to parse terraform file: %s"
+	ErrNoTerraformBlock       string = "error: %s: no terraform block found"
+	ErrRequiredVersionMissing string = "error: %s:%d: required_version not found in terraform block"
+	ErrRequiredVersionType    string = "error: %s:%d: required_version must be a string, found [%s]"
+)
+
+const (
+	terraformBlock          string = "terraform"
+	requiredVersionProperty string = "required_version"
+)
+
+// RequiredVersion contains the `required_version` constraint and where it was found
+type RequiredVersion struct {
+	Constraint string // the version constraint as set in the file (`>= 1.1.0`)
+	Filename   string // file the value was found in
+	Line       int    // line number of the attribute
+}
+
+// Location returns the file:line location of the constraint
+func (self *RequiredVersion) Location() string {
+	return fmt.Sprintf("%s:%d", self.Filename, self.Line)
+}
+
+// Parse converts the content into a hcl syntax body, with the filename used
+// for error messages
+func Parse(filename string, content []byte) (body *hclsyntax.Body, err error) {
+	file, diags := hclsyntax.ParseConfig(content, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		err = fmt.Errorf(ErrParsingFile, diags.Error())
+		return
+	}
+	body = file.Body.(*hclsyntax.Body)
+	return
+}
+
+// Blocks returns all top level blocks of the type requested
+func Blocks(body *hclsyntax.Body, blockType string) (blocks []*hclsyntax.Block) {
+	blocks = []*hclsyntax.Block{}
+	for _, block := range body.Blocks {
+		if block.Type == blockType {
+			blocks = append(blocks, block)
+		}
+	}
+	return
+}
+
+// stringValue evaluates the expression without any variables or functions
+// and returns its value if it is a string
+func stringValue(expr hclsyntax.Expression) (str string, ok bool) {
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() || val.IsNull() || !val.IsWhollyKnown() || val.Type() != cty.String {
+		return
+	}
+	str = val.AsString()
+	ok = true
+	return
+}
+
+// requiredVersions returns the `required_version` from the terraform blocks
+// passed, erroring only when a value is not a string
+func requiredVersions(filename string, content []byte, blocks []*hclsyntax.Block) (versions []*RequiredVersion, err error) {
+	versions = []*RequiredVersion{}
+
+	for _, block := range blocks {
+		attr, ok := block.Body.Attributes[requiredVersionProperty]
+		if !ok {
+			continue
+		}
+		line := attr.SrcRange.Start.Line
+		constraint, ok := stringValue(attr.Expr)
+		if !ok {
+			src := strings.TrimSpace(string(attr.Expr.Range().SliceBytes(content)))
+			err = fmt.Errorf(ErrRequiredVersionType, filename, line, src)
+			return
+		}
+		versions = append(versions, &RequiredVersion{
+			Constraint: constraint,
+			Filename:   filename,
+			Line:       line,
+		})
+	}
+	return
+}
+
+// RequiredVersions parses the terraform file content as hcl and returns the
+// `required_version` from every top level `terraform` block.
+//
+// Comments, other blocks and strings that mention required_version
+// are ignored.
+//
+// Errors contain the file name when there are no terraform blocks, and the
+// file:line location when there is no required_version attribute or when
+// the value is not a string.
+func RequiredVersions(filename string, content []byte) (versions []*RequiredVersion, err error) {
+	var (
+		body   *hclsyntax.Body
+		blocks []*hclsyntax.Block
+	)
+	versions = []*RequiredVersion{}
+
+	if body, err = Parse(filename, content); err != nil {
+		return
+	}
+	if blocks = Blocks(body, terraformBlock); len(blocks) == 0 {
+		err = fmt.Errorf(ErrNoTerraformBlock, filename)
+		return
+	}
+	if versions, err = requiredVersions(filename, content, blocks); err != nil {
+		return
+	}
+
+	if len(versions) == 0 {
+		err = fmt.Errorf(ErrRequiredVersionMissing, filename, blocks[0].DefRange().Start.Line)
+	}
+	return
+}
+
+// Constraint joins multiple required versions into a single constraint string
+func Constraint(versions []*RequiredVersion) (constraint string) {
+	var all = []string{}
+	for _, v := range versions {
+		all = append(all, v.Constraint)
+	}
+	constraint = strings.Join(all, ", ")
+	return
+}
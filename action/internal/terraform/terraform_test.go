@@ -0,0 +1,120 @@
+package terraform
+
+import (
+	"strings"
+	"testing"
+)
+
+type requiredVersionFixture struct {
+	Content       string
+	Expected      string
+	ExpectedLines []int
+	ErrorContains string
+}
+
+func TestTerraformRequiredVersions(t *testing.T) {
+
+	var tests = []*requiredVersionFixture{
+		{
+			Content: `terraform {
+				required_version = "1.6.5"
+			}`,
+			Expected:      "1.6.5",
+			ExpectedLines: []int{2},
+		},
+		{
+			Content:       `terraform {required_version = ">= 1.1.0"}`,
+			Expected:      ">= 1.1.0",
+			ExpectedLines: []int{1},
+		},
+		{
+			// ignores comments, other blocks and strings
+			Content: `# required_version = "0.1.0"
+// required_version = "0.2.0"
+/* required_version = "0.3.0" */
+locals {
+  required_version = "0.4.0"
+  note = "required_version = \"0.5.0\""
+}
+terraform {
+  backend "s3" {}
+}
+terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "6.25.0"
+    }
+  }
+  # required_version = "0.6.0"
+  required_version = "~> 1.5.0"
+}`,
+			Expected:      "~> 1.5.0",
+			ExpectedLines: []int{19},
+		},
+		{
+			// multiple terraform blocks
+			Content: `terraform {
+  required_version = ">= 1.3"
+}
+
+terraform {
+  required_version = "< 2.0"
+}`,
+			Expected:      ">= 1.3, < 2.0",
+			ExpectedLines: []int{2, 6},
+		},
+		{
+			Content: `
+
+locals { a = "b" }`,
+			ErrorContains: "versions.tf: no terraform block",
+		},
+		{
+			Content: `
+
+terraform {
+  backend "s3" {}
+}`,
+			ErrorContains: "versions.tf:3: required_version not found",
+		},
+		{
+			Content: `terraform {
+  required_version = 1
+}`,
+			ErrorContains: "versions.tf:2: required_version must be a string",
+		},
+		{
+			Content: `terraform {
+  required_version = var.version
+}`,
+			ErrorContains: "versions.tf:2: required_version must be a string, found [var.version]",
+		},
+		{
+			Content:       `terraform { required_version = "1.0.0"`,
+			ErrorContains: "failed to parse",
+		},
+	}
+
+	for i, test := range tests {
+		versions, err := RequiredVersions("versions.tf", []byte(test.Content))
+		if test.ErrorContains != "" {
+			if err == nil || !strings.Contains(err.Error(), test.ErrorContains) {
+				t.Errorf("[%d] expected error containing [%s] actual [%v]", i, test.ErrorContains, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+			continue
+		}
+		if actual := Constraint(versions); actual != test.Expected {
+			t.Errorf("[%d] expected [%s] actual [%s]", i, test.Expected, actual)
+		}
+		for idx, line := range test.ExpectedLines {
+			if versions[idx].Line != line {
+				t.Errorf("[%d:%d] expected line [%d] actual [%d]", i, idx, line, versions[idx].Line)
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package tickets
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DefaultPattern matches jira style issue keys (ABC-1234)
+const DefaultPattern string = `\b[A-Z][A-Z0-9]+-[0-9]+\b`
+
+// Find returns all unique ticket ids within the string that match the
+// regex pattern, in the order they are first found
+//
+// If the pattern is empty, then DefaultPattern is used
+func Find(s string, pattern string) (tickets []string, err error) {
+	var exp *regexp.Regexp
+
+	tickets = []string{}
+	if pattern == "" {
+		pattern = DefaultPattern
+	}
+	if exp, err = regexp.Compile(pattern); err != nil {
+		return
+	}
+
+	for _, match := range exp.FindAllString(s, -1) {
+		if !slices.Contains(tickets, match) {
+			tickets = append(tickets, match)
+		}
+	}
+	return
+}
+
+// Description returns the part of the string that describes the change,
+// removing the ticket id and any leading branch type segments.
+//
+// It uses the content after the first occurrence of the ticket and falls
+// back to the content before it when there is nothing after.
+//
+//	`ABC-1234-some-description` => `some-description`
+//	`feature/ABC-1234/add-thing` => `add-thing`
+//	`fix-the-thing-ABC-1234` => `fix-the-thing`
+func Description(s string, ticket string) (desc string) {
+	var before, after, found = strings.Cut(s, ticket)
+
+	desc = s
+	if ticket == "" || !found {
+		return
+	}
+
+	desc = strings.Trim(after, "-_/. ")
+	if desc == "" {
+		desc = strings.Trim(before, "-_/. ")
+	}
+	return
+}
@@ -0,0 +1,64 @@
+package tickets
+
+import (
+	"slices"
+	"testing"
+)
+
+type findFixture struct {
+	Test     string
+	Pattern  string
+	Expected []string
+}
+
+func TestTicketsFind(t *testing.T) {
+
+	var tests = []*findFixture{
+		{Test: "ABC-1234-some-description", Expected: []string{"ABC-1234"}},
+		{Test: "feature/ABC-1234/add-thing", Expected: []string{"ABC-1234"}},
+		{Test: "feature/ABC-1234-DEF-56-ABC-1234", Expected: []string{"ABC-1234", "DEF-56"}},
+		{Test: "renovate/terraform-1.5", Expected: []string{}},
+		{Test: "abc-1234-lower-case", Expected: []string{}},
+		{Test: "abc-1234-lower-case", Pattern: `(?i)[a-z]+-[0-9]+`, Expected: []string{"abc-1234"}},
+		{Test: "", Expected: []string{}},
+	}
+
+	for _, test := range tests {
+		actual, err := Find(test.Test, test.Pattern)
+		if err != nil {
+			t.Errorf("unexpected error finding tickets in [%s]: %s", test.Test, err.Error())
+		}
+		if !slices.Equal(actual, test.Expected) {
+			t.Errorf("error finding tickets in [%s], expected [%v] actual [%v]", test.Test, test.Expected, actual)
+		}
+	}
+
+	if _, err := Find("ABC-1234", "[a-z"); err == nil {
+		t.Errorf("expected an error for invalid pattern")
+	}
+}
+
+type descriptionFixture struct {
+	Test     string
+	Ticket   string
+	Expected string
+}
+
+func TestTicketsDescription(t *testing.T) {
+
+	var tests = []*descriptionFixture{
+		{Test: "ABC-1234-some-description", Ticket: "ABC-1234", Expected: "some-description"},
+		{Test: "feature/ABC-1234/add-thing", Ticket: "ABC-1234", Expected: "add-thing"},
+		{Test: "fix-the-thing-ABC-1234", Ticket: "ABC-1234", Expected: "fix-the-thing"},
+		{Test: "ABC-1234", Ticket: "ABC-1234", Expected: ""},
+		{Test: "no-ticket-here", Ticket: "", Expected: "no-ticket-here"},
+		{Test: "no-ticket-here", Ticket: "ABC-1", Expected: "no-ticket-here"},
+	}
+
+	for _, test := range tests {
+		actual := Description(test.Test, test.Ticket)
+		if actual != test.Expected {
+			t.Errorf("error getting description from [%s], expected [%s] actual [%s]", test.Test, test.Expected, actual)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package versionfiles
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Each setter finds the string value for the key and replaces only those
+// bytes within the content, so the rest of the file (ordering, indentation,
+// comments) is left untouched.
+
+// skipJSON reads past the next value in the decoder
+func skipJSON(dec *json.Decoder) (err error) {
+	var (
+		tok   json.Token
+		depth = 0
+	)
+	for {
+		if tok, err = dec.Token(); err != nil {
+			return
+		}
+		if d, ok := tok.(json.Delim); ok {
+			if d == '{' || d == '[' {
+				depth++
+			} else {
+				depth--
+			}
+		}
+		if depth == 0 {
+			return
+		}
+	}
+}
+
+// findJSON returns the byte offsets of the string value at path within the
+// object the decoder is about to read
+func findJSON(dec *json.Decoder, content []byte, path []string) (start int, end int, err error) {
+	var tok json.Token
+
+	if tok, err = dec.Token(); err != nil {
+		return
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '{' {
+		err = fmt.Errorf("not an object")
+		return
+	}
+	for dec.More() {
+		if tok, err = dec.Token(); err != nil {
+			return
+		}
+		if key, _ := tok.(string); key != path[0] {
+			if err = skipJSON(dec); err != nil {
+				return
+			}
+			continue
+		}
+		if len(path) > 1 {
+			return findJSON(dec, content, path[1:])
+		}
+
+		keyEnd := int(dec.InputOffset())
+		if tok, err = dec.Token(); err != nil {
+			return
+		}
+		if _, ok := tok.(string); !ok {
+			err = fmt.Errorf("not a string")
+			return
+		}
+		end = int(dec.InputOffset())
+		value := content[keyEnd:end]
+		value = value[bytes.IndexByte(value, ':')+1:]
+		start = end - len(bytes.TrimLeft(value, " \t\r\n"))
+		return
+	}
+	err = fmt.Errorf("not found")
+	return
+}
+
+// setJSON replaces the string at the dotted key path
+func setJSON(content []byte, key string, version string) (updated []byte, previous string, err error) {
+	var (
+		start, end int
+		quoted     []byte
+		dec        = json.NewDecoder(bytes.NewReader(content))
+	)
+	if start, end, err = findJSON(dec, content, strings.Split(key, ".")); err != nil {
+		err = fmt.Errorf(ErrKeyNotFound, key, FORMAT_JSON)
+		return
+	}
+	if err = json.Unmarshal(content[start:end], &previous); err != nil {
+		return
+	}
+	quoted, _ = json.Marshal(version)
+	updated = splice(content, start, end, quoted)
+	return
+}
+
+// offset converts the 1 based line and column into a byte offset; yaml
+// counts the column in characters, so multi-byte characters before the
+// value are stepped over one at a time
+func offset(content []byte, line int, column int) (pos int) {
+	for l := 1; l < line; l++ {
+		pos += bytes.IndexByte(content[pos:], '\n') + 1
+	}
+	for c := 1; c < column; c++ {
+		_, size := utf8.DecodeRune(content[pos:])
+		pos += size
+	}
+	return
+}
+
+// closingQuote returns the offset of the quote ending the yaml string that
+// starts at content[start]; double quoted strings escape with a backslash,
+// single quoted strings by repeating the quote
+func closingQuote(content []byte, start int) (end int) {
+	var quote = content[start]
+	for end = start + 1; end < len(content); end++ {
+		switch {
+		case quote == '"' && content[end] == '\\':
+			end++
+		case content[end] == quote && quote == '\'' && end+1 < len(content) && content[end+1] == '\'':
+			end++
+		case content[end] == quote:
+			return
+		}
+	}
+	return
+}
+
+// setYAML replaces the scalar at the dotted key path, keeping its quoting
+// style. Only the first document in the file is used.
+func setYAML(content []byte, key string, version string) (updated []byte, previous string, err error) {
+	var (
+		doc  = &yaml.Node{}
+		node *yaml.Node
+	)
+	if err = yaml.Unmarshal(content, doc); err != nil {
+		return
+	}
+	if len(doc.Content) > 0 {
+		node = doc.Content[0]
+	}
+	for _, part := range strings.Split(key, ".") {
+		var next *yaml.Node
+		if node != nil && node.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == part {
+					next = node.Content[i+1]
+				}
+			}
+		}
+		node = next
+	}
+	if node == nil {
+		err = fmt.Errorf(ErrKeyNotFound, key, FORMAT_YAML)
+		return
+	}
+	if node.Kind != yaml.ScalarNode {
+		err = fmt.Errorf(ErrNotAString, key, FORMAT_YAML)
+		return
+	}
+
+	var (
+		start = offset(content, node.Line, node.Column)
+		end   = start + len(node.Value)
+		value = version
+	)
+	switch node.Style {
+	case yaml.DoubleQuotedStyle:
+		end = closingQuote(content, start) + 1
+		value = strconv.Quote(version)
+	case yaml.SingleQuotedStyle:
+		end = closingQuote(content, start) + 1
+		value = "'" + strings.ReplaceAll(version, "'", "''") + "'"
+	}
+	previous = node.Value
+	updated = splice(content, start, end, []byte(value))
+	return
+}
+
+// setTOML replaces a basic or literal string value for the key within its
+// table. This is a line based scan rather than a full parser, so inline
+// tables and multi-line strings are not supported.
+func setTOML(content []byte, key string, version string) (updated []byte, previous string, err error) {
+	var (
+		parts   = strings.Split(key, ".")
+		table   = strings.Join(parts[:len(parts)-1], ".")
+		name    = regexp.QuoteMeta(parts[len(parts)-1])
+		pattern = regexp.MustCompile(`^\s*(?:` + name + `|"` + name + `")\s*=\s*(?P<value>"[^"]*"|'[^']*')`)
+		current = ""
+		pos     = 0
+	)
+	for _, line := range strings.SplitAfter(string(content), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			header, _, _ := strings.Cut(trimmed, "#")
+			current = strings.TrimSpace(strings.Trim(strings.TrimSpace(header), "[]"))
+		} else if current == table {
+			if m := pattern.FindStringSubmatchIndex(line); m != nil {
+				i := pattern.SubexpIndex("value")
+				start, end := pos+m[2*i], pos+m[2*i+1]
+				quote := content[start]
+				previous = string(content[start+1 : end-1])
+				updated = splice(content, start, end, []byte(string(quote)+version+string(quote)))
+				return
+			}
+		}
+		pos += len(line)
+	}
+	err = fmt.Errorf(ErrKeyNotFound, key, FORMAT_TOML)
+	return
+}
+
+// setGo replaces the string literal assigned to the const or var named key
+func setGo(content []byte, key string, version string) (updated []byte, previous string, err error) {
+	var (
+		fset = token.NewFileSet()
+		file *ast.File
+		lit  *ast.BasicLit
+	)
+	if file, err = parser.ParseFile(fset, "", content, parser.ParseComments); err != nil {
+		return
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		if spec, ok := n.(*ast.ValueSpec); ok {
+			for i, ident := range spec.Names {
+				if ident.Name == key && i < len(spec.Values) {
+					lit, _ = spec.Values[i].(*ast.BasicLit)
+				}
+			}
+		}
+		return lit == nil
+	})
+	if lit == nil {
+		err = fmt.Errorf(ErrKeyNotFound, key, FORMAT_GO)
+		return
+	}
+	if lit.Kind != token.STRING {
+		err = fmt.Errorf(ErrNotAString, key, FORMAT_GO)
+		return
+	}
+
+	value := strconv.Quote(version)
+	if strings.HasPrefix(lit.Value, "`") {
+		value = "`" + version + "`"
+	}
+	previous, _ = strconv.Unquote(lit.Value)
+	updated = splice(content, fset.Position(lit.Pos()).Offset, fset.Position(lit.End()).Offset, []byte(value))
+	return
+}
+
+// setText replaces the whole content, keeping surrounding whitespace
+func setText(content []byte, key string, version string) (updated []byte, previous string, err error) {
+	var (
+		trimmed = bytes.TrimSpace(content)
+		start   = len(content) - len(bytes.TrimLeft(content, " \t\r\n"))
+	)
+	previous = string(trimmed)
+	updated = splice(content, start, start+len(trimmed), []byte(version))
+	return
+}
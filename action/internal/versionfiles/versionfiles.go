@@ -0,0 +1,197 @@
+package versionfiles
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"opg-github-actions/action/internal/semver"
+)
+
+// Format is the type of version file, deciding how the version is found
+// and replaced
+type Format string
+
+const (
+	FORMAT_JSON Format = "json" // package.json, composer.json etc
+	FORMAT_YAML Format = "yaml" // helm Chart.yaml etc
+	FORMAT_TOML Format = "toml" // pyproject.toml, Cargo.toml
+	FORMAT_GO   Format = "go"   // a string const or var within a go file
+	FORMAT_TEXT Format = "text" // the whole file is the version
+)
+
+var formats = []Format{FORMAT_JSON, FORMAT_YAML, FORMAT_TOML, FORMAT_GO, FORMAT_TEXT}
+
+const (
+	ErrUnknownFormat string = "error: unknown version file format [%s], should be one of %v"
+	ErrKeyNotFound   string = "error: version key [%s] not found in [%s]"
+	ErrNotAString    string = "error: version key [%s] in [%s] is not a string"
+)
+
+// Target is a file to update, parsed from a `[format:]path[#key]` string
+type Target struct {
+	Path   string `json:"path"`
+	Format Format `json:"format"`
+	Key    string `json:"key"` // dotted path to the version field, when empty the default for the file is used
+}
+
+// Change reports the version found in a file and if it was updated
+type Change struct {
+	Path     string `json:"path"`
+	Format   Format `json:"format"`
+	Key      string `json:"key"`
+	Previous string `json:"previous"`
+	Version  string `json:"version"`
+	Changed  bool   `json:"changed"`
+}
+
+// setter replaces the string value at key within content
+type setter func(content []byte, key string, version string) (updated []byte, previous string, err error)
+
+var setters = map[Format]setter{
+	FORMAT_JSON: setJSON,
+	FORMAT_YAML: setYAML,
+	FORMAT_TOML: setTOML,
+	FORMAT_GO:   setGo,
+	FORMAT_TEXT: setText,
+}
+
+// DetectFormat returns the format based on the file extension, falling
+// back to text
+func DetectFormat(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FORMAT_JSON
+	case ".yaml", ".yml":
+		return FORMAT_YAML
+	case ".toml":
+		return FORMAT_TOML
+	case ".go":
+		return FORMAT_GO
+	}
+	return FORMAT_TEXT
+}
+
+// defaultKeys returns the keys to try, in order, when the target does not
+// set one
+func defaultKeys(format Format, path string) []string {
+	switch format {
+	case FORMAT_TOML:
+		if filepath.Base(path) == "Cargo.toml" {
+			return []string{"package.version"}
+		}
+		return []string{"project.version", "tool.poetry.version"}
+	case FORMAT_GO:
+		return []string{"Version"}
+	case FORMAT_TEXT:
+		return []string{""}
+	}
+	return []string{"version"}
+}
+
+// ParseTarget converts a `[format:]path[#key]` string into a target, like:
+//
+//	package.json
+//	yaml:charts/app/Chart.yaml#appVersion
+//	internal/version/version.go#Version
+//
+// The text before the first `:` is only used as the format when it is one
+// of the known formats, otherwise it is part of the path.
+func ParseTarget(s string) (target *Target, err error) {
+	target = &Target{}
+	s = strings.TrimSpace(s)
+
+	if format, path, found := strings.Cut(s, ":"); found && slices.Contains(formats, Format(format)) {
+		target.Format = Format(format)
+		s = path
+	}
+	target.Path, target.Key, _ = strings.Cut(s, "#")
+
+	if target.Format == "" {
+		target.Format = DetectFormat(target.Path)
+	}
+	if _, ok := setters[target.Format]; !ok {
+		err = fmt.Errorf(ErrUnknownFormat, target.Format, formats)
+	}
+	return
+}
+
+// ParseTargets splits the string on commas and new lines and parses each
+// non-empty entry
+func ParseTargets(s string) (targets []*Target, err error) {
+	targets = []*Target{}
+	for _, entry := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' }) {
+		var target *Target
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		if target, err = ParseTarget(entry); err != nil {
+			return
+		}
+		targets = append(targets, target)
+	}
+	return
+}
+
+// Normalise returns the version to write into files; the prefix is removed
+// from valid semvers unless keepPrefix is set, as most package formats
+// expect a bare `1.2.3`
+func Normalise(version string, keepPrefix bool) string {
+	if s := semver.FromString(version); s != nil && !keepPrefix {
+		return s.Stringy(false)
+	}
+	return version
+}
+
+// splice replaces content[start:end] with value
+func splice(content []byte, start int, end int, value []byte) (updated []byte) {
+	updated = append(updated, content[:start]...)
+	updated = append(updated, value...)
+	updated = append(updated, content[end:]...)
+	return
+}
+
+// Update sets the version within the target file (relative to directory),
+// only writing the file when the version differs. When write is false the
+// change is reported, but the file is left as is.
+func Update(lg *slog.Logger, directory string, target *Target, version string, write bool) (change *Change, err error) {
+	var (
+		content []byte
+		updated []byte
+		path    = target.Path
+		keys    = []string{target.Key}
+	)
+	lg = lg.With("operation", "Update", "path", target.Path, "format", target.Format)
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(directory, path)
+	}
+	if target.Key == "" {
+		keys = defaultKeys(target.Format, target.Path)
+	}
+	if content, err = os.ReadFile(path); err != nil {
+		return
+	}
+
+	change = &Change{Path: target.Path, Format: target.Format, Version: version}
+	for _, key := range keys {
+		change.Key = key
+		if updated, change.Previous, err = setters[target.Format](content, key, version); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return
+	}
+
+	change.Changed = !bytes.Equal(content, updated)
+	lg.Info("version file checked ... ", "key", change.Key, "previous", change.Previous, "version", version, "changed", change.Changed)
+	if change.Changed && write {
+		err = os.WriteFile(path, updated, 0644)
+	}
+	return
+}
@@ -0,0 +1,197 @@
+package versionfiles
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"opg-github-actions/action/internal/logger"
+)
+
+type updateFixture struct {
+	File          string
+	Target        string
+	Content       string
+	Version       string
+	Expected      string
+	Previous      string
+	ErrorContains string
+}
+
+func TestVersionFilesUpdate(t *testing.T) {
+	var lg = logger.New("error", "text")
+	var tests = []*updateFixture{
+		// json keeps formatting and other fields
+		{
+			File: "package.json", Target: "package.json", Version: "1.2.0", Previous: "1.1.0",
+			Content:  "{\n    \"name\": \"app\",\n    \"version\" :  \"1.1.0\",\n    \"private\": true\n}\n",
+			Expected: "{\n    \"name\": \"app\",\n    \"version\" :  \"1.2.0\",\n    \"private\": true\n}\n",
+		},
+		// nested json key, ignoring the same key elsewhere
+		{
+			File: "composer.json", Target: "composer.json#extra.version", Version: "2.0.0", Previous: "1.0.0",
+			Content:  `{"version":"0.0.1","require":{"version":"x"},"extra":{"a":[1,{"b":2}],"version":"1.0.0"}}`,
+			Expected: `{"version":"0.0.1","require":{"version":"x"},"extra":{"a":[1,{"b":2}],"version":"2.0.0"}}`,
+		},
+		{File: "package.json", Target: "package.json", Content: `{"name":"app"}`, Version: "1.0.0", ErrorContains: "not found"},
+		// yaml keeps comments and quoting
+		{
+			File: "Chart.yaml", Target: "Chart.yaml", Version: "1.2.0", Previous: "1.1.0",
+			Content:  "apiVersion: v2\n# chart version\nversion: 1.1.0 # bumped by ci\nappVersion: \"1.1.0\"\n",
+			Expected: "apiVersion: v2\n# chart version\nversion: 1.2.0 # bumped by ci\nappVersion: \"1.1.0\"\n",
+		},
+		{
+			File: "Chart.yaml", Target: "Chart.yaml#appVersion", Version: "v1.2.0", Previous: "1.1.0",
+			Content:  "version: 1.1.0\nappVersion: \"1.1.0\"\n",
+			Expected: "version: 1.1.0\nappVersion: \"v1.2.0\"\n",
+		},
+		{
+			File: "values.yml", Target: "values.yml#image.tag", Version: "1.2.0", Previous: "1.1.0",
+			Content:  "image:\n  repository: app\n  tag: '1.1.0'\n",
+			Expected: "image:\n  repository: app\n  tag: '1.2.0'\n",
+		},
+		// non-ascii characters before the value on the same line
+		{
+			File: "Chart.yaml", Target: "Chart.yaml#annotations.version", Version: "1.2.0", Previous: "1.1.0",
+			Content:  "annotations: {résumé: \"é\", version: 1.1.0}\n",
+			Expected: "annotations: {résumé: \"é\", version: 1.2.0}\n",
+		},
+		// escaped quotes within quoted values
+		{
+			File: "values.yaml", Target: "values.yaml#tag", Version: "1.2.0", Previous: `1.1.0 "rc"`,
+			Content:  "tag: \"1.1.0 \\\"rc\\\"\" # comment \"x\"\n",
+			Expected: "tag: \"1.2.0\" # comment \"x\"\n",
+		},
+		{
+			File: "values.yaml", Target: "values.yaml#tag", Version: "1.2.0", Previous: "1.1.0 'rc'",
+			Content:  "tag: '1.1.0 ''rc''' # it's\n",
+			Expected: "tag: '1.2.0' # it's\n",
+		},
+		// toml uses the table for the key
+		{
+			File: "pyproject.toml", Target: "pyproject.toml", Version: "1.2.0", Previous: "1.1.0",
+			Content:  "[build-system]\nversion = \"9.9.9\"\n\n[project]\nname = \"app\"\nversion = \"1.1.0\" # comment\n",
+			Expected: "[build-system]\nversion = \"9.9.9\"\n\n[project]\nname = \"app\"\nversion = \"1.2.0\" # comment\n",
+		},
+		// poetry is used when there is no project table
+		{
+			File: "pyproject.toml", Target: "pyproject.toml", Version: "1.2.0", Previous: "1.1.0",
+			Content:  "[tool.poetry]\nversion = '1.1.0'\n",
+			Expected: "[tool.poetry]\nversion = '1.2.0'\n",
+		},
+		{
+			File: "Cargo.toml", Target: "Cargo.toml", Version: "0.2.0", Previous: "0.1.0",
+			Content:  "[package]\nname = \"app\"\nversion = \"0.1.0\"\n",
+			Expected: "[package]\nname = \"app\"\nversion = \"0.2.0\"\n",
+		},
+		// go const and var
+		{
+			File: "version.go", Target: "version.go", Version: "1.2.0", Previous: "1.1.0",
+			Content:  "package version\n\n// Version of the app\nconst Version = \"1.1.0\"\n",
+			Expected: "package version\n\n// Version of the app\nconst Version = \"1.2.0\"\n",
+		},
+		{
+			File: "version.go", Target: "go:version.go#current", Version: "1.2.0", Previous: "1.1.0",
+			Content:  "package version\n\nvar (\n\tname    = \"app\"\n\tcurrent = `1.1.0`\n)\n",
+			Expected: "package version\n\nvar (\n\tname    = \"app\"\n\tcurrent = `1.2.0`\n)\n",
+		},
+		{File: "version.go", Target: "version.go", Content: "package version\n\nconst Version = 1\n", Version: "1.0.0", ErrorContains: "is not a string"},
+		// plain text
+		{File: "VERSION", Target: "VERSION", Content: "1.1.0\n", Version: "1.2.0", Previous: "1.1.0", Expected: "1.2.0\n"},
+		// unchanged
+		{File: "VERSION", Target: "VERSION", Content: "1.2.0\n", Version: "1.2.0", Previous: "1.2.0", Expected: "1.2.0\n"},
+	}
+
+	for i, test := range tests {
+		var dir = t.TempDir()
+		os.WriteFile(filepath.Join(dir, test.File), []byte(test.Content), 0644)
+
+		target, err := ParseTarget(test.Target)
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+			continue
+		}
+		change, err := Update(lg, dir, target, test.Version, true)
+		if test.ErrorContains != "" {
+			if err == nil || !strings.Contains(err.Error(), test.ErrorContains) {
+				t.Errorf("[%d] expected error containing [%s], actual [%v]", i, test.ErrorContains, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err.Error())
+			continue
+		}
+		actual, _ := os.ReadFile(filepath.Join(dir, test.File))
+		if string(actual) != test.Expected {
+			t.Errorf("[%d] expected:\n%s\nactual:\n%s", i, test.Expected, string(actual))
+		}
+		if change.Previous != test.Previous || change.Changed != (test.Content != test.Expected) {
+			t.Errorf("[%d] expected previous [%s], actual [%s] changed [%t]", i, test.Previous, change.Previous, change.Changed)
+		}
+	}
+}
+
+type targetFixture struct {
+	Value         string
+	Expected      []Target
+	ErrorContains string
+}
+
+func TestVersionFilesParseTargets(t *testing.T) {
+	var tests = []*targetFixture{
+		{
+			Value: "package.json, yaml:charts/app/Chart.yaml#appVersion\nVERSION",
+			Expected: []Target{
+				{Path: "package.json", Format: FORMAT_JSON},
+				{Path: "charts/app/Chart.yaml", Format: FORMAT_YAML, Key: "appVersion"},
+				{Path: "VERSION", Format: FORMAT_TEXT},
+			},
+		},
+		// text before the : is only a format when it is a known format
+		{
+			Value: "deploy:prod/Chart.yaml#appVersion, xml:VERSION",
+			Expected: []Target{
+				{Path: "deploy:prod/Chart.yaml", Format: FORMAT_YAML, Key: "appVersion"},
+				{Path: "xml:VERSION", Format: FORMAT_TEXT},
+			},
+		},
+	}
+
+	for i, test := range tests {
+		actual, err := ParseTargets(test.Value)
+		if test.ErrorContains != "" {
+			if err == nil || !strings.Contains(err.Error(), test.ErrorContains) {
+				t.Errorf("[%d] expected error containing [%s], actual [%v]", i, test.ErrorContains, err)
+			}
+			continue
+		}
+		if len(actual) != len(test.Expected) {
+			t.Errorf("[%d] expected [%d] targets, actual [%d]", i, len(test.Expected), len(actual))
+			continue
+		}
+		for j, target := range actual {
+			if *target != test.Expected[j] {
+				t.Errorf("[%d] expected [%+v], actual [%+v]", i, test.Expected[j], *target)
+			}
+		}
+	}
+}
+
+func TestVersionFilesNormalise(t *testing.T) {
+	var tests = map[string]string{
+		"v1.2.3":        "1.2.3",
+		"1.2.3":         "1.2.3",
+		"v1.2.3-beta.1": "1.2.3-beta.1",
+		"2026.10":       "2026.10",
+	}
+	for in, expected := range tests {
+		if actual := Normalise(in, false); actual != expected {
+			t.Errorf("expected [%s], actual [%s]", expected, actual)
+		}
+	}
+	if actual := Normalise("v1.2.3", true); actual != "v1.2.3" {
+		t.Errorf("expected prefix to be kept, actual [%s]", actual)
+	}
+}
@@ -1,6 +1,8 @@
 #!/usr/bin/env bash
-# Bash script to change terraform versions via tfswitch for local dev.
-# Ensures that tfswitch, jq, versions.tf are present before running
+# Bash script to change terraform versions for local dev.
+# Finds the version from the current directory (versions.tf, .terraform-version
+# or .tool-versions), installs it via the opg-actions terraform-install command and puts it
+# at the front of the PATH. Source this script to keep the PATH change.
 #
 
 set -e
@@ -8,12 +10,8 @@ set -e
 current_dir=$(pwd)
 # directory of this script
 script_dir=$( cd -- "$( dirname -- "${BASH_SOURCE[0]}" )" &> /dev/null && pwd )
-# versions.tf file path
-file="${current_dir}/versions.tf"
-# path to tfswitch (from which call)
-tfswitch_path=$(which tfswitch)
-# path to jq
-jq_path=$(which jq)
+# where to find available versions
+releases_index="${TERRAFORM_RELEASES_INDEX:-https://releases.hashicorp.com/terraform/index.json}"
 # verbose output defaults to 0
 verbose=0
 
@@ -21,6 +19,7 @@ verbose=0
 # to parent scope
 export TERRAFORM_VERSION_RANGE=""
 export TERRAFORM_INSTALLED_VERSION=""
+export TERRAFORM_BINARY=""
 
 
 # fetch option data
@@ -31,35 +30,31 @@ do
     esac
 done
 
-# If ${file} is not a file, then fail
-if [ ! -f "${file}" ]; then
-    echo -e "error: Terraform versions file not found: [${file}]" >&2
+# Check go installed
+if ! command -v go &> /dev/null; then
+    echo -e "error: go not found" >&2
     exit 1
 fi
 
-# If tfswitch path is not a file, then fail
-if [ ! -f "${tfswitch_path}" ]; then
-    echo -e "error: tfswitch not found: [${tfswitch_path}]" >&2
-    exit 1
-fi
-
-# Check jq installed
-if [ ! -f "${jq_path}" ]; then
-    echo -e "error: jq not found: [${jq_path}]" >&2
-    exit 1
-fi
+# output values from a command, selected by key
+value() {
+    sed -n -r "s/^${1}=(.*)$/\1/p"
+}
 
-# Now fetch the terraform version using sibling script
-TERRAFORM_VERSION_RANGE=$(cat ${file} | ${script_dir}/./terraform-version.sh)
+# Now fetch the terraform version and resolve it against the available versions
+versions=$(LOG_LEVEL=ERROR go run "${script_dir}/../action/cmd/opg-actions" terraform-version \
+    --directory="${current_dir}" \
+    --available-versions="${releases_index}")
+TERRAFORM_VERSION_RANGE=$(echo "${versions}" | value "version")
+resolved=$(echo "${versions}" | value "resolved")
 
-# Now run tfswitch
+# Now install the resolved version
 echo
-echo -e "Running tfswitch.."
-tfswitch
-
-# Now capture the version in use - can only do this after tfswitch has run
-# - strip quotes from the json output
-TERRAFORM_INSTALLED_VERSION=$(terraform version -json | jq '.terraform_version' | sed -e 's/"//g')
+echo -e "Installing terraform ${resolved}.."
+installed=$(LOG_LEVEL=ERROR go run "${script_dir}/../action/cmd/opg-actions" terraform-install --version="${resolved}")
+TERRAFORM_BINARY=$(echo "${installed}" | value "path")
+TERRAFORM_INSTALLED_VERSION=$(echo "${installed}" | value "version")
+export PATH="$(dirname "${TERRAFORM_BINARY}"):${PATH}"
 
 # If verbose is turned on, output the info from this run
 if [ "${verbose}" == "1" ]; then
@@ -67,10 +62,9 @@ if [ "${verbose}" == "1" ]; then
     echo -e "--- Info ---"
     echo -e "- Called from directory: [${current_dir}]"
     echo -e "- Script location: [${script_dir}]"
-    echo -e "- Terraform versions file found: [${file}]"
-    echo -e "- tfswitch command path: [${tfswitch_path}]"
-    echo -e "- jq command path: [${jq_path}]"
+    echo -e "- Releases index: [${releases_index}]"
     echo -e "- Terraform version range found: [${TERRAFORM_VERSION_RANGE}]"
     echo -e "- Terraform installed version: [${TERRAFORM_INSTALLED_VERSION}]"
+    echo -e "- Terraform binary: [${TERRAFORM_BINARY}]"
     echo -e "---"
 fi
@@ -1,7 +1,15 @@
 #!/usr/bin/env bash
-# Usage: Get the semver range from the content of a
-# versions.tf file
+# Usage: terraform-version.sh [directory]
+#
+# Output the terraform version to use for the directory (defaults to the
+# current directory). Uses the terraform-version command, so checks
+# .terraform-version and .tool-versions files before the required_version
+# within versions.tf.
+set -e
+# directory of this script
+script_dir=$( cd -- "$( dirname -- "${BASH_SOURCE[0]}" )" &> /dev/null && pwd )
+directory="${1:-$(pwd)}"
 
-# Presume the imcing stream is a `versions.tf` file and
-# parse out the required_version value
-sed -r -n 's/.*required_version.*"(.*)"$/\1/p' <&0
+# only errors are logged so the output is just the key=value pairs
+LOG_LEVEL=ERROR go run "${script_dir}/../action/cmd/opg-actions" terraform-version --directory="${directory}" \
+    | sed -n -r 's/^version=(.*)$/\1/p'